@@ -3,19 +3,32 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/gtrs/validation-service/internal/auth"
 	"github.com/gtrs/validation-service/internal/config"
+	"github.com/gtrs/validation-service/internal/fx"
+	"github.com/gtrs/validation-service/internal/grpcserver"
 	"github.com/gtrs/validation-service/internal/handlers"
+	"github.com/gtrs/validation-service/internal/metrics"
 	"github.com/gtrs/validation-service/internal/middleware"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/repository"
 	"github.com/gtrs/validation-service/internal/services"
+	"github.com/gtrs/validation-service/internal/tlsconfig"
+	"github.com/gtrs/validation-service/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -25,16 +38,134 @@ func main() {
 	// Setup logging
 	setupLogging(cfg.LogLevel)
 
+	// Setup tracing before anything creates spans
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:       cfg.TracingExporter,
+		OTLPEndpoint:   cfg.OTLPEndpoint,
+		OTLPInsecure:   cfg.OTLPInsecure,
+		SampleRatio:    cfg.TracingSampleRatio,
+		ServiceName:    cfg.ServiceName,
+		ServiceVersion: cfg.ServiceVersion,
+		Environment:    cfg.Environment,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize tracing")
+	}
+
+	// Setup TLS, reloading rotated certificates until shutdown
+	certs, err := setupTLS(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize TLS")
+	}
+	certsCtx, stopWatchingCerts := context.WithCancel(context.Background())
+	defer stopWatchingCerts()
+	if certs != nil {
+		if err := certs.Watch(certsCtx); err != nil {
+			logrus.WithError(err).Warn("Certificate file watching disabled")
+		}
+	}
+
+	// Setup authentication and authorization
+	authenticator, policy, err := setupAuth(cfg, certs)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize authentication")
+	}
+
+	// Setup per-client rate limits and the in-flight request cap
+	limits, err := setupLimits(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid rate limits")
+	}
+
+	// Initialize storage
+	store, err := setupStorage(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize storage")
+	}
+	defer store.close()
+
 	// Initialize services
-	validationService := services.NewValidationService()
+	serviceMetrics := metrics.New()
+	thresholds := services.RiskThresholds{Review: cfg.RiskReviewThreshold, Fail: cfg.RiskFailThreshold}
+	if err := thresholds.Validate(); err != nil {
+		logrus.WithError(err).Fatal("Invalid risk thresholds")
+	}
+	serviceOptions := []services.Option{
+		services.WithRiskThresholds(thresholds),
+		services.WithMetrics(serviceMetrics),
+		services.WithTimeouts(services.Timeouts{
+			Request: time.Duration(cfg.ValidationTimeout) * time.Millisecond,
+			Rule:    time.Duration(cfg.RuleTimeout) * time.Millisecond,
+		}),
+		services.WithSanctionsDir(cfg.SanctionsDir),
+	}
+	if cfg.FXRatesFile != "" {
+		rates, err := fx.LoadStaticProvider(cfg.FXRatesFile)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load FX rates")
+		}
+		serviceOptions = append(serviceOptions, services.WithRateProvider(rates))
+	}
+	if store.idempotency != nil {
+		serviceOptions = append(serviceOptions, services.WithIdempotency(store.idempotency))
+	}
+	if cfg.RulesDir != "" {
+		// The rule files are authoritative: a change through /api/rules would be lost on
+		// the next reload
+		serviceOptions = append(serviceOptions, services.WithReadOnlyRules())
+	}
+
+	validationService := services.NewValidationService(store.results, serviceOptions...)
+
+	var ruleLoader *services.RuleLoader
+	if cfg.RulesDir != "" {
+		ruleLoader = services.NewRuleLoader(cfg.RulesDir, cfg.SanctionsDir)
+		if err := ruleLoader.Reload(validationService); err != nil {
+			logrus.WithError(err).Fatal("Failed to load validation rules")
+		}
+	}
+
+	// Reload rules on file change or SIGHUP
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	if ruleLoader != nil {
+		if err := ruleLoader.Watch(watchCtx, validationService); err != nil {
+			logrus.WithError(err).Warn("Rule file watching disabled")
+		}
+		go reloadRulesOnSignal(watchCtx, ruleLoader, validationService, cfg.RulesDir)
+	}
+
+	// Process asynchronous validation jobs, resuming any left unfinished
+	jobRunner := services.NewJobRunner(validationService, store.jobs, services.JobRunnerConfig{
+		Workers:          cfg.BatchWorkers,
+		QueueSize:        cfg.JobQueueSize,
+		CallbackAttempts: cfg.JobCallbackAttempts,
+		CallbackTimeout:  time.Duration(cfg.JobCallbackTimeout) * time.Second,
+		CallbackBackoff:  time.Second,
+
+		CallbackAllowPrivate: cfg.JobCallbackAllowPrivate,
+	})
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	if err := jobRunner.Start(jobsCtx); err != nil {
+		logrus.WithError(err).Fatal("Failed to start job runner")
+	}
 
 	// Setup router
-	router := setupRouter(cfg, validationService)
+	router := setupRouter(cfg, validationService, jobRunner, ruleLoader, serviceMetrics, authenticator, policy, limits)
 
-	// Create HTTP server
+	// Create HTTP server. Request contexts derive from requestsCtx so that
+	// validations still running when shutdown times out are cancelled.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: router,
+		Addr:        fmt.Sprintf(":%d", cfg.Port),
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return requestsCtx },
+	}
+	grpcOptions := grpcserver.RateLimit(limits.ipLimiter, limits.limiter, limits.inFlight)
+	if certs != nil {
+		server.TLSConfig = certs.TLSConfig()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
 	}
 
 	// Start server in a goroutine
@@ -43,31 +174,85 @@ func main() {
 			"port":    cfg.Port,
 			"env":     cfg.Environment,
 			"service": "validation-service",
+			"tls":     certs != nil,
 		}).Info("Starting Validation Service")
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		serve := server.ListenAndServe
+		if certs != nil {
+			// Certificates come from the TLS config so that they can be reloaded
+			serve = func() error { return server.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			logrus.WithError(err).Fatal("Failed to start server")
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
+	// Start gRPC server on its own port, sharing the validation service
+	grpcServer := grpcserver.NewServer(validationService, authenticator, policy, grpcOptions...)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to listen for gRPC")
+	}
+	go func() {
+		logrus.WithField("port", cfg.GRPCPort).Info("Starting gRPC server")
+
+		if err := grpcServer.Serve(grpcListener); err != nil && err != grpc.ErrServerStopped {
+			logrus.WithError(err).Fatal("Failed to start gRPC server")
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the servers
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logrus.Info("Shutting down server...")
 
-	// Give outstanding requests 30 seconds to complete
+	// Give outstanding requests and streams 30 seconds to complete on both servers
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	grpcStopped := make(chan struct{})
+	go func() {
+		stopGRPCServer(ctx, grpcServer)
+		close(grpcStopped)
+	}()
+
 	if err := server.Shutdown(ctx); err != nil {
-		logrus.WithError(err).Fatal("Server forced to shutdown")
+		logrus.WithError(err).Error("Server forced to shutdown")
+		cancelRequests()
+	}
+	<-grpcStopped
+
+	// Stop background jobs after their current chunk; unfinished jobs resume on restart
+	stopJobs()
+	jobRunner.Wait()
+
+	// Export spans still buffered
+	if err := shutdownTracing(ctx); err != nil {
+		logrus.WithError(err).Error("Failed to flush traces")
 	}
 
 	logrus.Info("Server exited")
 }
 
+// stopGRPCServer waits for in-flight RPCs to finish, cancelling them once ctx expires
+func stopGRPCServer(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logrus.Error("gRPC server forced to shutdown")
+		server.Stop()
+		<-done
+	}
+}
+
 func setupLogging(level string) {
 	logrus.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: time.RFC3339,
@@ -87,7 +272,220 @@ func setupLogging(level string) {
 	}
 }
 
-func setupRouter(cfg *config.Config, validationService *services.ValidationService) *gin.Engine {
+// reloadRulesOnSignal reloads the rule set each time the process receives SIGHUP
+func reloadRulesOnSignal(ctx context.Context, loader *services.RuleLoader, service *services.ValidationService, dir string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := loader.Reload(service); err != nil {
+				logrus.WithError(err).WithField("rules_dir", dir).Error("Rule reload failed, keeping previous rule set")
+				continue
+			}
+			logrus.WithField("rules_dir", dir).Info("Rules reloaded on SIGHUP")
+		}
+	}
+}
+
+// setupTLS loads the server certificate and client CA bundle, or returns nil when no
+// certificate is configured and the servers use plaintext
+func setupTLS(cfg *config.Config) (*tlsconfig.Manager, error) {
+	tlsConfig := tlsconfig.Config{
+		CertFile:     cfg.TLSCertFile,
+		KeyFile:      cfg.TLSKeyFile,
+		ClientCAFile: cfg.TLSClientCAFile,
+		ClientAuth:   cfg.TLSClientAuth,
+	}
+	if !tlsConfig.Enabled() {
+		if cfg.Environment == "production" {
+			logrus.Warn("TLS disabled: no certificate configured, serving plaintext")
+		}
+		return nil, nil
+	}
+	return tlsconfig.New(tlsConfig)
+}
+
+// setupAuth creates the authenticator for the configured API keys, JWT keys and, when
+// the TLS setup verifies them, client certificates, and the policy granting callers
+// their permissions. Without any credentials the API is open, which is only allowed
+// outside production.
+func setupAuth(cfg *config.Config, certs *tlsconfig.Manager) (*auth.Authenticator, *auth.Policy, error) {
+	apiKeys, err := auth.ParseAPIKeys(cfg.APIKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authenticator, err := auth.New(auth.Config{
+		APIKeys:            apiKeys,
+		HMACSecret:         []byte(cfg.JWTHMACSecret),
+		JWKSFile:           cfg.JWKSFile,
+		Issuer:             cfg.JWTIssuer,
+		Audience:           cfg.JWTAudience,
+		ClientCertificates: certs != nil && certs.VerifiesClients(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !authenticator.Enabled() {
+		if cfg.Environment == "production" {
+			return nil, nil, fmt.Errorf("no API keys, JWT keys or client certificates configured")
+		}
+		logrus.Warn("Authentication disabled: no API keys, JWT keys or client certificates configured")
+	}
+
+	policy, err := auth.NewPolicy(nil, nil)
+	if cfg.PolicyFile != "" {
+		policy, err = auth.LoadPolicy(cfg.PolicyFile)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range apiKeys {
+		if !policy.HasSubject(key.Subject) {
+			logrus.WithField("subject", key.Subject).Warn("API key has no roles in the policy; its requests will be forbidden")
+		}
+	}
+
+	return authenticator, policy, nil
+}
+
+// limits holds the request limits selected by configuration
+type limits struct {
+	ipLimiter      *ratelimit.Limiter  // by client IP before authentication; nil when disabled
+	limiter        *ratelimit.Limiter  // nil when no client is rate limited
+	inFlight       *ratelimit.InFlight // nil when concurrency is not capped
+	trustedProxies []string            // whose X-Forwarded-For gives the client IP; none when empty
+}
+
+// setupLimits creates the per-client rate limiter from the default and per-client
+// quotas, the per-IP limiter applied before authentication, and the in-flight request
+// cap. IP addresses listed in the quotas override the per-IP quota too.
+func setupLimits(cfg *config.Config) (*limits, error) {
+	quotas, err := ratelimit.ParseQuotas(cfg.RateLimitQuotas)
+	if err != nil {
+		return nil, err
+	}
+	trustedProxies, err := parseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitRPS < 0 || cfg.RateLimitBurst < 0 || cfg.RateLimitIPRPS < 0 || cfg.RateLimitIPBurst < 0 || cfg.MaxInFlight < 0 {
+		return nil, fmt.Errorf("rate limits, bursts and in-flight cap must not be negative")
+	}
+
+	defaultQuota := ratelimit.Quota{Rate: cfg.RateLimitRPS, Burst: cfg.RateLimitBurst}
+	if defaultQuota.Burst == 0 {
+		defaultQuota.Burst = ratelimit.DefaultBurst(defaultQuota.Rate)
+	}
+
+	result := limits{trustedProxies: trustedProxies}
+	if limiter := ratelimit.New(defaultQuota, quotas); limiter.Enabled() {
+		result.limiter = limiter
+		logrus.WithFields(logrus.Fields{
+			"rate":    defaultQuota.Rate,
+			"burst":   defaultQuota.Burst,
+			"clients": len(quotas),
+		}).Info("Rate limiting enabled")
+	}
+
+	ipQuota := ratelimit.Quota{Rate: cfg.RateLimitIPRPS, Burst: cfg.RateLimitIPBurst}
+	if ipQuota.Burst == 0 {
+		ipQuota.Burst = ratelimit.DefaultBurst(ipQuota.Rate)
+	}
+	if !ipQuota.Unlimited() {
+		result.ipLimiter = ratelimit.New(ipQuota, quotas)
+		logrus.WithFields(logrus.Fields{
+			"rate":  ipQuota.Rate,
+			"burst": ipQuota.Burst,
+		}).Info("Per-IP rate limiting enabled")
+	}
+	if cfg.MaxInFlight > 0 {
+		result.inFlight = ratelimit.NewInFlight(cfg.MaxInFlight)
+	}
+	return &result, nil
+}
+
+// parseTrustedProxies parses a comma separated list of proxy IP addresses and CIDRs
+func parseTrustedProxies(spec string) ([]string, error) {
+	var proxies []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return nil, fmt.Errorf("trusted proxy %q must be an IP address or CIDR", entry)
+		}
+		proxies = append(proxies, entry)
+	}
+	return proxies, nil
+}
+
+// storage holds the repositories selected by configuration
+type storage struct {
+	results     repository.ValidationResultRepository
+	jobs        repository.JobRepository
+	idempotency repository.IdempotencyRepository // nil when idempotency is disabled
+	close       func()                           // releases any underlying connections
+}
+
+// setupStorage creates the repositories for the configured storage backend
+func setupStorage(cfg *config.Config) (*storage, error) {
+	idempotencyTTL := time.Duration(cfg.IdempotencyTTL) * time.Hour
+
+	switch cfg.StorageBackend {
+	case "memory":
+		store := &storage{
+			results: repository.NewMemoryResultRepository(),
+			jobs:    repository.NewMemoryJobRepository(),
+			close:   func() {},
+		}
+		if idempotencyTTL > 0 {
+			store.idempotency = repository.NewMemoryIdempotencyRepository(idempotencyTTL)
+		}
+		return store, nil
+	case "postgres":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db, err := repository.OpenPostgres(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+
+		store := &storage{close: func() { db.Close() }}
+
+		if store.results, err = repository.NewPostgresResultRepository(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		if store.jobs, err = repository.NewPostgresJobRepository(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		if idempotencyTTL > 0 {
+			if store.idempotency, err = repository.NewPostgresIdempotencyRepository(ctx, db, idempotencyTTL); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.StorageBackend)
+	}
+}
+
+func setupRouter(cfg *config.Config, validationService *services.ValidationService, jobRunner *services.JobRunner, ruleLoader *services.RuleLoader, serviceMetrics *metrics.Metrics, authenticator *auth.Authenticator, policy *auth.Policy, limits *limits) *gin.Engine {
 	// Set Gin mode based on environment
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -95,10 +493,25 @@ func setupRouter(cfg *config.Config, validationService *services.ValidationServi
 
 	router := gin.New()
 
-	// Add middleware
+	// The client IP, which anonymous callers are rate limited by, is taken from
+	// X-Forwarded-For only on requests relayed by a trusted proxy. Trusting any other
+	// peer would let callers choose their own rate limit bucket.
+	if err := router.SetTrustedProxies(limits.trustedProxies); err != nil {
+		logrus.WithError(err).Fatal("Invalid trusted proxies")
+	}
+
+	// Add middleware. Tracing comes first so that the request span covers the others;
+	// Prometheus scrapes are not traced.
+	router.Use(otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/metrics"
+	})))
 	router.Use(middleware.Logger())
 	router.Use(middleware.Recovery())
 	router.Use(middleware.CORS())
+	router.Use(middleware.Metrics(serviceMetrics))
+
+	// Prometheus scrape endpoint
+	router.GET("/metrics", gin.WrapH(serviceMetrics.Handler()))
 
 	// Health endpoints
 	healthHandler := handlers.NewHealthHandler()
@@ -112,13 +525,87 @@ func setupRouter(cfg *config.Config, validationService *services.ValidationServi
 		}
 	}
 
+	// Everything except health checks requires authentication when credentials are
+	// configured, and each route group a permission granted by the caller's roles
+	secured := api.Group("")
+
+	// Client IPs are limited before authentication, so that requests with missing or
+	// invalid credentials cannot be sent at an unlimited rate
+	if limits.ipLimiter != nil {
+		secured.Use(middleware.RateLimitByIP(limits.ipLimiter))
+	}
+
+	authorize := func(permission auth.Permission) []gin.HandlerFunc {
+		return nil
+	}
+	if authenticator.Enabled() {
+		secured.Use(middleware.Authenticate(authenticator))
+		authorize = func(permission auth.Permission) []gin.HandlerFunc {
+			return []gin.HandlerFunc{middleware.Authorize(policy, permission)}
+		}
+	}
+
+	// Rules, results and jobs belong to the tenant selected by the caller's token or
+	// policy binding, or by the X-Tenant-ID header
+	secured.Use(middleware.Tenant(policy))
+
+	// Each caller, or client IP when anonymous, gets its own request quota so that one
+	// client cannot starve the others; health checks are never limited
+	if limits.limiter != nil {
+		secured.Use(middleware.RateLimit(limits.limiter))
+	}
+	if limits.inFlight != nil {
+		secured.Use(middleware.MaxInFlight(limits.inFlight))
+	}
+
 	// Validation endpoints (basic structure for now)
 	validationHandler := handlers.NewValidationHandler(validationService)
-	validation := api.Group("/validate")
+	batchHandler := handlers.NewBatchHandler(validationService, cfg.BatchWorkers, cfg.BatchMaxItems)
+	validate := secured.Group("/validate", authorize(auth.PermissionCreateValidations)...)
+	{
+		validate.POST("", validationHandler.ValidateTransaction)
+		validate.POST("/batch", batchHandler.ValidateBatch)
+	}
+	results := secured.Group("/validate", authorize(auth.PermissionReadValidations)...)
+	{
+		results.GET("", validationHandler.SearchValidationResults)
+		results.GET("/:id", validationHandler.GetValidationResult)
+	}
+
+	// Asynchronous validation job endpoints
+	jobHandler := handlers.NewJobHandler(jobRunner, cfg.BatchMaxItems)
+	submitJobs := secured.Group("/jobs", authorize(auth.PermissionCreateValidations)...)
+	{
+		submitJobs.POST("", jobHandler.SubmitJob)
+	}
+	jobs := secured.Group("/jobs", authorize(auth.PermissionReadValidations)...)
 	{
-		validation.POST("", validationHandler.ValidateTransaction)
-		validation.GET("/:id", validationHandler.GetValidationResult)
+		jobs.GET("/:id", jobHandler.GetJob)
+		jobs.GET("/:id/results", jobHandler.GetJobResults)
+	}
+
+	// Rule management endpoints
+	ruleHandler := handlers.NewRuleHandler(validationService)
+	readRules := secured.Group("/rules", authorize(auth.PermissionReadRules)...)
+	{
+		readRules.GET("", ruleHandler.ListRules)
+		readRules.GET("/:id", ruleHandler.GetRule)
+	}
+	rules := secured.Group("/rules", authorize(auth.PermissionWriteRules)...)
+	{
+		rules.POST("", ruleHandler.CreateRule)
+		rules.PUT("/:id", ruleHandler.UpdateRule)
+		rules.DELETE("/:id", ruleHandler.DeleteRule)
+		rules.POST("/:id/enable", ruleHandler.EnableRule)
+		rules.POST("/:id/disable", ruleHandler.DisableRule)
+	}
+
+	// Operational endpoints
+	adminHandler := handlers.NewAdminHandler(validationService, ruleLoader)
+	admin := secured.Group("/admin", authorize(auth.PermissionAdmin)...)
+	{
+		admin.POST("/rules/reload", adminHandler.ReloadRules)
 	}
 
 	return router
-}
\ No newline at end of file
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrNoCredentials is returned when a request carries neither an API key nor a bearer token
+	ErrNoCredentials = errors.New("no credentials")
+
+	// ErrInvalidCredentials is returned when an API key or token is not accepted
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authentication methods recorded on an identity
+const (
+	MethodAPIKey     = "api_key"
+	MethodJWT        = "jwt"
+	MethodClientCert = "client_cert"
+)
+
+// CertificateSubjectPrefix starts the subject of every client certificate identity, so
+// a certificate name never matches an API key name or token subject in the policy.
+// API key names cannot contain a colon and tokens with such a subject are refused.
+const CertificateSubjectPrefix = "cert:"
+
+// RolesClaim is the token claim listing the caller's roles, as an array or a
+// space separated string
+const RolesClaim = "roles"
+
+// TenantClaim is the token claim binding the caller to one tenant
+const TenantClaim = "tenant"
+
+// jwtLeeway allows for clock skew between the token issuer and this service
+const jwtLeeway = 30 * time.Second
+
+// Identity is an authenticated caller
+type Identity struct {
+	Subject string   // API key name or token subject
+	Method  string   // how the caller authenticated
+	Roles   []string // roles carried in the token; policy bindings are added on top
+	Tenant  string   // tenant carried in the token, if any
+}
+
+// Caller returns the identity as recorded on validation results
+func (i *Identity) Caller() *models.Caller {
+	return &models.Caller{Subject: i.Subject, Method: i.Method}
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity stored in ctx, if any
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(*Identity)
+	return identity, ok
+}
+
+// APIKey is an accepted API key, stored only as the SHA-256 hash of the key
+type APIKey struct {
+	Subject string
+	Hash    [sha256.Size]byte
+}
+
+// HashAPIKey returns the hex SHA-256 hash of a key, as configured in API_KEYS
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAPIKeys parses a comma separated list of "subject:sha256-hex" entries
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		subject, hash, ok := strings.Cut(entry, ":")
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("API key entry %q must be subject:sha256-hex", entry)
+		}
+		decoded, err := hex.DecodeString(hash)
+		if err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("API key %q hash must be 64 hex characters", subject)
+		}
+
+		key := APIKey{Subject: subject}
+		copy(key.Hash[:], decoded)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Config lists the accepted credentials. Tokens may be signed with the HMAC secret or
+// with any key in the JWKS file.
+type Config struct {
+	APIKeys            []APIKey
+	HMACSecret         []byte
+	JWKSFile           string
+	Issuer             string // required iss claim, if set
+	Audience           string // required aud claim, if set
+	ClientCertificates bool   // accept TLS client certificates verified by the server
+}
+
+// Authenticator checks API keys and JWT bearer tokens
+type Authenticator struct {
+	apiKeys    map[[sha256.Size]byte]string // key hash to subject
+	hmacSecret []byte
+	jwks       map[string]interface{} // key ID to public key
+	clientCert bool
+	parser     *jwt.Parser
+}
+
+// New creates an authenticator, loading the JWKS file if one is configured
+func New(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys:    make(map[[sha256.Size]byte]string, len(cfg.APIKeys)),
+		hmacSecret: cfg.HMACSecret,
+		clientCert: cfg.ClientCertificates,
+	}
+	for _, key := range cfg.APIKeys {
+		a.apiKeys[key.Hash] = key.Subject
+	}
+
+	var methods []string
+	if len(cfg.HMACSecret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if cfg.JWKSFile != "" {
+		jwks, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = jwks
+		methods = append(methods, "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+// Enabled reports whether any credentials are configured. Without them every request
+// is let through anonymously.
+func (a *Authenticator) Enabled() bool {
+	return len(a.apiKeys) > 0 || len(a.hmacSecret) > 0 || len(a.jwks) > 0 || a.clientCert
+}
+
+// Authenticate identifies the caller from an API key or a bearer token (either may be
+// empty). An API key takes precedence when both are given.
+func (a *Authenticator) Authenticate(apiKey, bearerToken string) (*Identity, error) {
+	switch {
+	case apiKey != "":
+		subject, ok := a.apiKeys[sha256.Sum256([]byte(apiKey))]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+		}
+		return &Identity{Subject: subject, Method: MethodAPIKey}, nil
+	case bearerToken != "":
+		return a.authenticateToken(bearerToken)
+	default:
+		return nil, ErrNoCredentials
+	}
+}
+
+// AuthenticateTLS identifies the caller like Authenticate, falling back to the client
+// certificate of the connection when neither an API key nor a token is given. state is
+// nil for plaintext connections.
+func (a *Authenticator) AuthenticateTLS(apiKey, bearerToken string, state *tls.ConnectionState) (*Identity, error) {
+	if apiKey == "" && bearerToken == "" && a.clientCert && state != nil && len(state.VerifiedChains) > 0 {
+		return CertificateIdentity(state.VerifiedChains[0][0])
+	}
+	return a.Authenticate(apiKey, bearerToken)
+}
+
+// CertificateIdentity identifies the caller by the common name of a verified client
+// certificate, or by its first URI name (such as a SPIFFE ID) when it has none,
+// prefixed with CertificateSubjectPrefix
+func CertificateIdentity(cert *x509.Certificate) (*Identity, error) {
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.URIs) > 0 {
+		subject = cert.URIs[0].String()
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("%w: client certificate has no common name or URI", ErrInvalidCredentials)
+	}
+	return &Identity{Subject: CertificateSubjectPrefix + subject, Method: MethodClientCert}, nil
+}
+
+// authenticateToken verifies a JWT and identifies the caller by its subject
+func (a *Authenticator) authenticateToken(raw string) (*Identity, error) {
+	if len(a.hmacSecret) == 0 && len(a.jwks) == 0 {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrInvalidCredentials)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(raw, claims, a.verificationKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	if strings.HasPrefix(subject, CertificateSubjectPrefix) {
+		return nil, fmt.Errorf("%w: token subject must not start with %q", ErrInvalidCredentials, CertificateSubjectPrefix)
+	}
+
+	roles, err := tokenRoles(claims[RolesClaim])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	tenantID, ok := claims[TenantClaim].(string)
+	if _, present := claims[TenantClaim]; present && !ok {
+		return nil, fmt.Errorf("%w: %s claim must be a string", ErrInvalidCredentials, TenantClaim)
+	}
+	if err := tenant.Validate(tenantID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	return &Identity{Subject: subject, Method: MethodJWT, Roles: roles, Tenant: tenantID}, nil
+}
+
+// tokenRoles reads the roles claim, which may be absent
+func tokenRoles(claim interface{}) ([]string, error) {
+	switch value := claim.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Fields(value), nil
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			name, ok := role.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s claim must contain strings", RolesClaim)
+			}
+			roles = append(roles, name)
+		}
+		return roles, nil
+	default:
+		return nil, fmt.Errorf("%s claim must be a string or an array", RolesClaim)
+	}
+}
+
+// verificationKey selects the key for a token: the HMAC secret for HS* tokens,
+// otherwise the JWKS key named by the kid header
+func (a *Authenticator) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := a.jwks[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
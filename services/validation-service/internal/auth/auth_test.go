@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret-at-least-32-bytes-long")
+
+func signHMAC(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "transaction-service",
+		"iss": "https://issuer.example",
+		"aud": "validation-service",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("transaction-service:" + HashAPIKey("secret-1") + ", batch:" + HashAPIKey("secret-2"))
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "batch", keys[1].Subject)
+
+	keys, err = ParseAPIKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, spec := range []string{"no-hash", ":" + HashAPIKey("x"), "short:abcd", "bad:" + HashAPIKey("x")[:62] + "zz"} {
+		_, err := ParseAPIKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestAuthenticator_APIKey(t *testing.T) {
+	keys, err := ParseAPIKeys("transaction-service:" + HashAPIKey("secret-1"))
+	require.NoError(t, err)
+	authenticator, err := New(Config{APIKeys: keys})
+	require.NoError(t, err)
+	assert.True(t, authenticator.Enabled())
+
+	identity, err := authenticator.Authenticate("secret-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: "transaction-service", Method: MethodAPIKey}, identity)
+
+	_, err = authenticator.Authenticate("secret-2", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate("", "")
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	// Tokens are refused when no signing keys are configured
+	_, err = authenticator.Authenticate("", signHMAC(t, validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthenticator_HMACToken(t *testing.T) {
+	authenticator, err := New(Config{HMACSecret: testSecret, Issuer: "https://issuer.example", Audience: "validation-service"})
+	require.NoError(t, err)
+
+	identity, err := authenticator.Authenticate("", signHMAC(t, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: "transaction-service", Method: MethodJWT}, identity)
+
+	tests := map[string]func(jwt.MapClaims){
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://other.example" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other-service" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			modify(claims)
+			_, err := authenticator.Authenticate("", signHMAC(t, claims))
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("roles", func(t *testing.T) {
+		claims := validClaims()
+		claims["roles"] = []string{"analyst", "ops"}
+		identity, err := authenticator.Authenticate("", signHMAC(t, claims))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"analyst", "ops"}, identity.Roles)
+
+		claims["roles"] = "validator analyst"
+		identity, err = authenticator.Authenticate("", signHMAC(t, claims))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"validator", "analyst"}, identity.Roles)
+
+		claims["roles"] = 42
+		_, err = authenticator.Authenticate("", signHMAC(t, claims))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("tenant", func(t *testing.T) {
+		claims := validClaims()
+		claims["tenant"] = "retail"
+		identity, err := authenticator.Authenticate("", signHMAC(t, claims))
+		require.NoError(t, err)
+		assert.Equal(t, "retail", identity.Tenant)
+
+		for _, invalid := range []interface{}{42, "not a tenant"} {
+			claims["tenant"] = invalid
+			_, err = authenticator.Authenticate("", signHMAC(t, claims))
+			assert.ErrorIs(t, err, ErrInvalidCredentials, invalid)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("another-secret"))
+		require.NoError(t, err)
+		_, err = authenticator.Authenticate("", token)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = authenticator.Authenticate("", token)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+func TestAuthenticator_JWKSToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y)},
+	}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+
+	authenticator, err := New(Config{JWKSFile: path})
+	require.NoError(t, err)
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, validClaims())
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	identity, err := authenticator.Authenticate("", sign(jwt.SigningMethodRS256, "rsa-1", rsaKey))
+	require.NoError(t, err)
+	assert.Equal(t, "transaction-service", identity.Subject)
+
+	_, err = authenticator.Authenticate("", sign(jwt.SigningMethodES256, "ec-1", ecKey))
+	assert.NoError(t, err)
+
+	_, err = authenticator.Authenticate("", sign(jwt.SigningMethodRS256, "unknown", rsaKey))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// HMAC tokens are not accepted without a configured secret
+	_, err = authenticator.Authenticate("", signHMAC(t, validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestLoadJWKS_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"empty":     `{"keys": []}`,
+		"bad curve": `{"keys": [{"kty": "EC", "kid": "k", "crv": "P-192", "x": "AQ", "y": "AQ"}]}`,
+		"off curve": `{"keys": [{"kty": "EC", "kid": "k", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`,
+		"bad type":  `{"keys": [{"kty": "OKP", "kid": "k"}]}`,
+		"not json":  `keys`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadJWKS(path)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAuthenticator_ClientCertificate(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "transaction-service"}}
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	keys, err := ParseAPIKeys("batch:" + HashAPIKey("secret-1"))
+	require.NoError(t, err)
+	authenticator, err := New(Config{APIKeys: keys, ClientCertificates: true})
+	require.NoError(t, err)
+
+	identity, err := authenticator.AuthenticateTLS("", "", state)
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: "cert:transaction-service", Method: MethodClientCert}, identity)
+
+	// Explicit credentials take precedence over the certificate
+	identity, err = authenticator.AuthenticateTLS("secret-1", "", state)
+	require.NoError(t, err)
+	assert.Equal(t, "batch", identity.Subject)
+
+	// Unverified certificates are ignored
+	_, err = authenticator.AuthenticateTLS("", "", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}})
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	_, err = authenticator.AuthenticateTLS("", "", nil)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	// Certificates are refused unless enabled
+	withoutCerts, err := New(Config{APIKeys: keys})
+	require.NoError(t, err)
+	_, err = withoutCerts.AuthenticateTLS("", "", state)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+	certsOnly, err := New(Config{ClientCertificates: true})
+	require.NoError(t, err)
+	assert.True(t, certsOnly.Enabled())
+}
+
+func TestCertificateIdentity(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://example.org/ns/payments/sa/gateway")
+	require.NoError(t, err)
+
+	identity, err := CertificateIdentity(&x509.Certificate{URIs: []*url.URL{spiffe}})
+	require.NoError(t, err)
+	assert.Equal(t, "cert:spiffe://example.org/ns/payments/sa/gateway", identity.Subject)
+
+	_, err = CertificateIdentity(&x509.Certificate{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestCertificateIdentity_DoesNotMatchOtherSubjects(t *testing.T) {
+	policy, err := NewPolicy(nil, map[string][]string{"transaction-service": {"validator"}})
+	require.NoError(t, err)
+	policy.BindTenant("transaction-service", "retail")
+
+	// A certificate named like an API key gains none of its roles or tenant
+	identity, err := CertificateIdentity(&x509.Certificate{Subject: pkix.Name{CommonName: "transaction-service"}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, policy.Authorize(identity, PermissionCreateValidations), ErrForbidden)
+	assert.Empty(t, policy.Tenant(identity))
+
+	// Certificates are bound by their prefixed subject
+	policy, err = NewPolicy(nil, map[string][]string{"cert:transaction-service": {"validator"}})
+	require.NoError(t, err)
+	assert.NoError(t, policy.Authorize(identity, PermissionCreateValidations))
+
+	// Tokens cannot claim a certificate subject
+	authenticator, err := New(Config{HMACSecret: testSecret})
+	require.NoError(t, err)
+	claims := validClaims()
+	claims["sub"] = "cert:transaction-service"
+	_, err = authenticator.Authenticate("", signHMAC(t, claims))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrForbidden is returned when a caller lacks the permission a request needs
+var ErrForbidden = errors.New("forbidden")
+
+// Permission is an action on the API that roles grant
+type Permission string
+
+// Permissions checked by the REST and gRPC APIs
+const (
+	PermissionCreateValidations Permission = "validations:create" // validate transactions, batches and jobs
+	PermissionReadValidations   Permission = "validations:read"   // fetch and search results and jobs
+	PermissionReadRules         Permission = "rules:read"
+	PermissionWriteRules        Permission = "rules:write"
+	PermissionAdmin             Permission = "admin"       // operational endpoints
+	PermissionAnyTenant         Permission = "tenants:any" // act for a tenant named by the request
+)
+
+var knownPermissions = map[Permission]bool{
+	PermissionCreateValidations: true,
+	PermissionReadValidations:   true,
+	PermissionReadRules:         true,
+	PermissionWriteRules:        true,
+	PermissionAdmin:             true,
+	PermissionAnyTenant:         true,
+}
+
+// DefaultRoles are the roles used when the policy file does not define its own
+var DefaultRoles = map[string][]Permission{
+	"validator":   {PermissionCreateValidations, PermissionReadValidations},
+	"analyst":     {PermissionReadValidations, PermissionReadRules},
+	"rule-admin":  {PermissionReadRules, PermissionWriteRules},
+	"ops":         {PermissionAdmin},
+	"all-tenants": {PermissionAnyTenant},
+}
+
+// policyFile is the on-disk format of a policy file
+//
+//	roles:
+//	  auditor: [validations:read]
+//	subjects:
+//	  transaction-service: [validator]
+//	  jane@example.com: [analyst, rule-admin]
+//	tenants:
+//	  retail-gateway: retail
+type policyFile struct {
+	Roles    map[string][]Permission `json:"roles" yaml:"roles"`
+	Subjects map[string][]string     `json:"subjects" yaml:"subjects"`
+	Tenants  map[string]string       `json:"tenants" yaml:"tenants"`
+}
+
+// Policy maps roles to permissions and callers to roles and tenants. A caller's roles
+// are those bound to its subject plus any carried in its token.
+type Policy struct {
+	roles    map[string]map[Permission]bool
+	subjects map[string][]string
+	tenants  map[string]string // subject to the only tenant it may act for
+}
+
+// NewPolicy creates a policy, using DefaultRoles when roles is empty. Every permission
+// and every role bound to a subject must be known.
+func NewPolicy(roles map[string][]Permission, subjects map[string][]string) (*Policy, error) {
+	if len(roles) == 0 {
+		roles = DefaultRoles
+	}
+
+	policy := &Policy{
+		roles:    make(map[string]map[Permission]bool, len(roles)),
+		subjects: make(map[string][]string, len(subjects)),
+	}
+	for role, permissions := range roles {
+		granted := make(map[Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			if !knownPermissions[permission] {
+				return nil, fmt.Errorf("role %q grants unknown permission %q", role, permission)
+			}
+			granted[permission] = true
+		}
+		policy.roles[role] = granted
+	}
+	for subject, bound := range subjects {
+		for _, role := range bound {
+			if _, ok := policy.roles[role]; !ok {
+				return nil, fmt.Errorf("subject %q is bound to unknown role %q", subject, role)
+			}
+		}
+		policy.subjects[subject] = bound
+	}
+
+	return policy, nil
+}
+
+// LoadPolicy reads a YAML or JSON policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy %s: %w", path, err)
+	}
+
+	var file policyFile
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse policy %s: %w", path, err)
+	}
+
+	policy, err := NewPolicy(file.Roles, file.Subjects)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	for subject, tenantID := range file.Tenants {
+		if tenantID == tenant.Default {
+			return nil, fmt.Errorf("policy %s: subject %q is bound to an empty tenant", path, subject)
+		}
+		if err := tenant.Validate(tenantID); err != nil {
+			return nil, fmt.Errorf("policy %s: subject %q: %w", path, subject, err)
+		}
+		policy.BindTenant(subject, tenantID)
+	}
+	return policy, nil
+}
+
+// BindTenant restricts a subject to acting for one tenant
+func (p *Policy) BindTenant(subject, tenantID string) {
+	if p.tenants == nil {
+		p.tenants = make(map[string]string)
+	}
+	p.tenants[subject] = tenantID
+}
+
+// Tenant returns the tenant the caller is bound to by its token or the policy, or ""
+// when it may choose one. A token's tenant claim takes precedence.
+func (p *Policy) Tenant(identity *Identity) string {
+	if identity.Tenant != "" {
+		return identity.Tenant
+	}
+	return p.tenants[identity.Subject]
+}
+
+// TenantScope returns the tenant the caller is bound to, if any, and whether an unbound
+// caller may act for a tenant other than the default one
+func (p *Policy) TenantScope(identity *Identity) (bound string, anyTenant bool) {
+	return p.Tenant(identity), p.Authorize(identity, PermissionAnyTenant) == nil
+}
+
+// HasSubject reports whether any roles are bound to the subject
+func (p *Policy) HasSubject(subject string) bool {
+	return len(p.subjects[subject]) > 0
+}
+
+// Roles returns the caller's roles, sorted and without duplicates
+func (p *Policy) Roles(identity *Identity) []string {
+	seen := make(map[string]bool)
+	var roles []string
+	for _, role := range append(append([]string{}, p.subjects[identity.Subject]...), identity.Roles...) {
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Authorize returns ErrForbidden, explaining which permission is missing and which
+// roles grant it, unless one of the caller's roles grants the permission
+func (p *Policy) Authorize(identity *Identity, permission Permission) error {
+	roles := p.Roles(identity)
+	for _, role := range roles {
+		if p.roles[role][permission] {
+			return nil
+		}
+	}
+
+	var granting []string
+	for role, permissions := range p.roles {
+		if permissions[permission] {
+			granting = append(granting, role)
+		}
+	}
+	sort.Strings(granting)
+
+	grantedBy := "no role grants it"
+	if len(granting) > 0 {
+		grantedBy = "granted by " + strings.Join(granting, ", ")
+	}
+	held := "no roles"
+	if len(roles) > 0 {
+		held = "roles " + strings.Join(roles, ", ")
+	}
+	return fmt.Errorf("%w: missing permission %s (%s); %s has %s",
+		ErrForbidden, permission, grantedBy, identity.Subject, held)
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_DefaultRoles(t *testing.T) {
+	policy, err := NewPolicy(nil, map[string][]string{
+		"transaction-service": {"validator"},
+		"jane":                {"analyst", "rule-admin"},
+	})
+	require.NoError(t, err)
+
+	validator := &Identity{Subject: "transaction-service", Method: MethodAPIKey}
+	assert.NoError(t, policy.Authorize(validator, PermissionCreateValidations))
+	assert.NoError(t, policy.Authorize(validator, PermissionReadValidations))
+
+	err = policy.Authorize(validator, PermissionWriteRules)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.EqualError(t, err, "forbidden: missing permission rules:write (granted by rule-admin); transaction-service has roles validator")
+
+	jane := &Identity{Subject: "jane", Method: MethodJWT}
+	assert.NoError(t, policy.Authorize(jane, PermissionWriteRules))
+	assert.ErrorIs(t, policy.Authorize(jane, PermissionCreateValidations), ErrForbidden)
+
+	unknown := &Identity{Subject: "someone", Method: MethodAPIKey}
+	assert.EqualError(t, policy.Authorize(unknown, PermissionReadRules),
+		"forbidden: missing permission rules:read (granted by analyst, rule-admin); someone has no roles")
+}
+
+func TestPolicy_TokenRoles(t *testing.T) {
+	policy, err := NewPolicy(nil, map[string][]string{"jane": {"analyst"}})
+	require.NoError(t, err)
+
+	identity := &Identity{Subject: "jane", Method: MethodJWT, Roles: []string{"ops", "analyst", "unknown"}}
+	assert.Equal(t, []string{"analyst", "ops", "unknown"}, policy.Roles(identity))
+	assert.NoError(t, policy.Authorize(identity, PermissionAdmin))
+	assert.ErrorIs(t, policy.Authorize(identity, PermissionWriteRules), ErrForbidden)
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	_, err := NewPolicy(map[string][]Permission{"auditor": {"results:delete"}}, nil)
+	assert.Error(t, err)
+
+	_, err = NewPolicy(nil, map[string][]string{"jane": {"auditor"}})
+	assert.Error(t, err)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+roles:
+  auditor: [validations:read]
+subjects:
+  jane: [auditor]
+`), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	jane := &Identity{Subject: "jane", Method: MethodJWT}
+	assert.NoError(t, policy.Authorize(jane, PermissionReadValidations))
+	assert.True(t, policy.HasSubject("jane"))
+
+	// Roles defined in the file replace the defaults
+	jane.Roles = []string{"ops"}
+	assert.EqualError(t, policy.Authorize(jane, PermissionAdmin),
+		"forbidden: missing permission admin (no role grants it); jane has roles auditor, ops")
+
+	jsonPath := filepath.Join(dir, "policy.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"subjects": {"svc": ["validator"]}, "extra": true}`), 0o600))
+	_, err = LoadPolicy(jsonPath)
+	assert.Error(t, err, "unknown fields are rejected")
+}
+
+func TestPolicy_Tenants(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+subjects:
+  retail-gateway: [validator]
+  auditor: [analyst, all-tenants]
+tenants:
+  retail-gateway: retail
+`), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, "retail", policy.Tenant(&Identity{Subject: "retail-gateway", Method: MethodAPIKey}))
+	assert.Equal(t, "", policy.Tenant(&Identity{Subject: "jane", Method: MethodJWT}))
+
+	// A token's tenant claim takes precedence over the policy
+	assert.Equal(t, "corporate", policy.Tenant(&Identity{Subject: "retail-gateway", Method: MethodJWT, Tenant: "corporate"}))
+
+	// Only callers with tenants:any may name a tenant themselves
+	bound, anyTenant := policy.TenantScope(&Identity{Subject: "jane", Method: MethodJWT})
+	assert.Equal(t, "", bound)
+	assert.False(t, anyTenant)
+	bound, anyTenant = policy.TenantScope(&Identity{Subject: "auditor", Method: MethodAPIKey})
+	assert.Equal(t, "", bound)
+	assert.True(t, anyTenant)
+
+	require.NoError(t, os.WriteFile(path, []byte(`tenants: {svc: "bad tenant"}`), 0o600))
+	_, err = LoadPolicy(path)
+	assert.Error(t, err)
+}
@@ -11,9 +11,13 @@ import (
 // Config holds all configuration for the validation service
 type Config struct {
 	Port        int    `json:"port"`
+	GRPCPort    int    `json:"grpc_port"`
 	Environment string `json:"environment"`
 	LogLevel    string `json:"log_level"`
 
+	// Storage configuration
+	StorageBackend string `json:"storage_backend"` // memory or postgres
+
 	// Database configuration
 	DatabaseURL      string `json:"database_url"`
 	DatabaseHost     string `json:"database_host"`
@@ -29,6 +33,63 @@ type Config struct {
 	RedisPassword string `json:"redis_password"`
 	RedisDB       int    `json:"redis_db"`
 
+	// Rules configuration
+	RulesDir     string `json:"rules_dir"`     // directory of YAML/JSON rule files; built-in rules when empty
+	SanctionsDir string `json:"sanctions_dir"` // directory SANCTIONS_SCREENING list files are loaded from
+
+	// FX configuration
+	FXRatesFile string `json:"fx_rates_file"` // static rate table for rules with a base_currency
+
+	// Batch configuration
+	BatchWorkers  int `json:"batch_workers"`   // concurrent validations per batch
+	BatchMaxItems int `json:"batch_max_items"` // largest accepted batch
+
+	// Async job configuration
+	JobQueueSize            int  `json:"job_queue_size"`
+	JobCallbackAttempts     int  `json:"job_callback_attempts"`
+	JobCallbackTimeout      int  `json:"job_callback_timeout"`       // seconds
+	JobCallbackAllowPrivate bool `json:"job_callback_allow_private"` // permit callbacks to internal addresses
+
+	// Timeout configuration
+	ValidationTimeout int `json:"validation_timeout_ms"` // whole validation, milliseconds; 0 disables
+	RuleTimeout       int `json:"rule_timeout_ms"`       // default per rule, milliseconds; 0 disables
+
+	// Idempotency configuration
+	IdempotencyTTL int `json:"idempotency_ttl"` // hours a key is remembered; 0 disables idempotency
+
+	// Risk scoring configuration
+	RiskReviewThreshold float64 `json:"risk_review_threshold"`
+	RiskFailThreshold   float64 `json:"risk_fail_threshold"`
+
+	// Authentication configuration
+	APIKeys       string `json:"-"` // comma separated subject:sha256-hex entries
+	JWTHMACSecret string `json:"-"`
+	JWKSFile      string `json:"jwks_file"`
+	JWTIssuer     string `json:"jwt_issuer"`
+	JWTAudience   string `json:"jwt_audience"`
+	PolicyFile    string `json:"policy_file"` // role definitions and subject bindings; default roles when empty
+
+	// TLS configuration; the HTTP and gRPC servers serve plaintext when no certificate is set
+	TLSCertFile     string `json:"tls_cert_file"`
+	TLSKeyFile      string `json:"tls_key_file"`
+	TLSClientCAFile string `json:"tls_client_ca_file"` // CA bundle client certificates are verified against
+	TLSClientAuth   string `json:"tls_client_auth"`    // none, optional or require
+
+	// Rate limiting configuration
+	RateLimitRPS     float64 `json:"rate_limit_rps"`      // default requests per second per client; 0 disables
+	RateLimitBurst   int     `json:"rate_limit_burst"`    // default bucket size; one second's worth when 0
+	RateLimitQuotas  string  `json:"rate_limit_quotas"`   // comma separated client:rate:burst overrides
+	RateLimitIPRPS   float64 `json:"rate_limit_ip_rps"`   // requests per second per client IP before authentication; 0 disables
+	RateLimitIPBurst int     `json:"rate_limit_ip_burst"` // bucket size per client IP; one second's worth when 0
+	MaxInFlight      int     `json:"max_in_flight"`       // concurrent API requests; 0 disables
+	TrustedProxies   string  `json:"trusted_proxies"`     // comma separated proxy IPs/CIDRs allowed to set X-Forwarded-For
+
+	// Tracing configuration
+	TracingExporter    string  `json:"tracing_exporter"` // none, stdout or otlp
+	OTLPEndpoint       string  `json:"otlp_endpoint"`    // host:port of the OTLP gRPC collector
+	OTLPInsecure       bool    `json:"otlp_insecure"`
+	TracingSampleRatio float64 `json:"tracing_sample_ratio"`
+
 	// Service configuration
 	ServiceName    string `json:"service_name"`
 	ServiceVersion string `json:"service_version"`
@@ -41,9 +102,13 @@ func Load() *Config {
 
 	cfg := &Config{
 		Port:        getEnvAsInt("PORT", 8081),
+		GRPCPort:    getEnvAsInt("GRPC_PORT", 9081),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
 
+		// Storage
+		StorageBackend: getEnv("STORAGE_BACKEND", "memory"),
+
 		// Database
 		DatabaseURL:      getEnv("DATABASE_URL", ""),
 		DatabaseHost:     getEnv("DB_HOST", "localhost"),
@@ -59,6 +124,63 @@ func Load() *Config {
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 		RedisDB:       getEnvAsInt("REDIS_DB", 0),
 
+		// Rules
+		RulesDir:     getEnv("RULES_DIR", ""),
+		SanctionsDir: getEnv("SANCTIONS_DIR", ""),
+
+		// FX
+		FXRatesFile: getEnv("FX_RATES_FILE", ""),
+
+		// Batch
+		BatchWorkers:  getEnvAsInt("BATCH_WORKERS", 8),
+		BatchMaxItems: getEnvAsInt("BATCH_MAX_ITEMS", 50000),
+
+		// Async jobs
+		JobQueueSize:            getEnvAsInt("JOB_QUEUE_SIZE", 100),
+		JobCallbackAttempts:     getEnvAsInt("JOB_CALLBACK_ATTEMPTS", 5),
+		JobCallbackTimeout:      getEnvAsInt("JOB_CALLBACK_TIMEOUT", 10),
+		JobCallbackAllowPrivate: getEnvAsBool("JOB_CALLBACK_ALLOW_PRIVATE", false),
+
+		// Timeouts
+		ValidationTimeout: getEnvAsInt("VALIDATION_TIMEOUT_MS", 5000),
+		RuleTimeout:       getEnvAsInt("RULE_TIMEOUT_MS", 1000),
+
+		// Idempotency
+		IdempotencyTTL: getEnvAsInt("IDEMPOTENCY_TTL", 24),
+
+		// Risk scoring
+		RiskReviewThreshold: getEnvAsFloat("RISK_REVIEW_THRESHOLD", 50),
+		RiskFailThreshold:   getEnvAsFloat("RISK_FAIL_THRESHOLD", 100),
+
+		// Authentication
+		APIKeys:       getEnv("API_KEYS", ""),
+		JWTHMACSecret: getEnv("JWT_HMAC_SECRET", ""),
+		JWKSFile:      getEnv("JWT_JWKS_FILE", ""),
+		JWTIssuer:     getEnv("JWT_ISSUER", ""),
+		JWTAudience:   getEnv("JWT_AUDIENCE", ""),
+		PolicyFile:    getEnv("AUTH_POLICY_FILE", ""),
+
+		// TLS
+		TLSCertFile:     getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:   getEnv("TLS_CLIENT_AUTH", "none"),
+
+		// Rate limiting
+		RateLimitRPS:     getEnvAsFloat("RATE_LIMIT_RPS", 0),
+		RateLimitBurst:   getEnvAsInt("RATE_LIMIT_BURST", 0),
+		RateLimitQuotas:  getEnv("RATE_LIMIT_QUOTAS", ""),
+		RateLimitIPRPS:   getEnvAsFloat("RATE_LIMIT_IP_RPS", 0),
+		RateLimitIPBurst: getEnvAsInt("RATE_LIMIT_IP_BURST", 0),
+		MaxInFlight:      getEnvAsInt("MAX_IN_FLIGHT", 0),
+		TrustedProxies:   getEnv("TRUSTED_PROXIES", ""),
+
+		// Tracing
+		TracingExporter:    getEnv("TRACING_EXPORTER", "none"),
+		OTLPEndpoint:       getEnv("OTLP_ENDPOINT", "localhost:4317"),
+		OTLPInsecure:       getEnvAsBool("OTLP_INSECURE", true),
+		TracingSampleRatio: getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+
 		// Service
 		ServiceName:    getEnv("SERVICE_NAME", "validation-service"),
 		ServiceVersion: getEnv("SERVICE_VERSION", "1.0.0-SNAPSHOT"),
@@ -76,8 +198,10 @@ func Load() *Config {
 
 	logrus.WithFields(logrus.Fields{
 		"port":        cfg.Port,
+		"grpc_port":   cfg.GRPCPort,
 		"environment": cfg.Environment,
 		"log_level":   cfg.LogLevel,
+		"storage":     cfg.StorageBackend,
 		"service":     cfg.ServiceName,
 		"version":     cfg.ServiceVersion,
 	}).Info("Configuration loaded")
@@ -101,6 +225,24 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func buildDatabaseURL(cfg *Config) string {
 	return "postgres://" + cfg.DatabaseUser + ":" + cfg.DatabasePassword +
 		"@" + cfg.DatabaseHost + ":" + strconv.Itoa(cfg.DatabasePort) +
@@ -0,0 +1,41 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateNotFound is returned when a provider has no rate for a currency pair
+var ErrRateNotFound = errors.New("fx rate not found")
+
+// Rate converts amounts in From into To: to = from * Value
+type Rate struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Value  decimal.Decimal `json:"rate"`
+	Source string          `json:"source"`
+	AsOf   time.Time       `json:"as_of,omitempty"`
+}
+
+// Convert applies the rate to an amount in the From currency
+func (r Rate) Convert(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(r.Value)
+}
+
+// RateProvider supplies exchange rates between currencies
+type RateProvider interface {
+	// Rate returns the rate converting one unit of from into to, or ErrRateNotFound
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// ProviderFunc adapts a function to the RateProvider interface, e.g. to plug in a
+// market data client
+type ProviderFunc func(ctx context.Context, from, to string) (Rate, error)
+
+// Rate calls f
+func (f ProviderFunc) Rate(ctx context.Context, from, to string) (Rate, error) {
+	return f(ctx, from, to)
+}
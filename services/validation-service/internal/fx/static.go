@@ -0,0 +1,130 @@
+package fx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// crossRatePrecision is the number of decimal places kept when deriving cross rates
+const crossRatePrecision = 12
+
+// rateTable is the on-disk format of a static rate file: how many units of each
+// currency one unit of the base currency buys
+//
+//	base: USD
+//	as_of: 2024-01-02T00:00:00Z
+//	rates:
+//	  EUR: "0.9150"
+//	  JPY: "141.62"
+type rateTable struct {
+	Base  string            `json:"base" yaml:"base"`
+	AsOf  time.Time         `json:"as_of" yaml:"as_of"`
+	Rates map[string]string `json:"rates" yaml:"rates"`
+}
+
+// StaticProvider serves rates from a fixed table quoted against one base currency.
+// Rates between two non-base currencies are derived through the base.
+type StaticProvider struct {
+	base   string
+	asOf   time.Time
+	source string
+	rates  map[string]decimal.Decimal // units of currency per one unit of base
+}
+
+// NewStaticProvider creates a provider from rates quoted against base
+func NewStaticProvider(base string, rates map[string]decimal.Decimal, asOf time.Time, source string) (*StaticProvider, error) {
+	provider := &StaticProvider{
+		base:   base,
+		asOf:   asOf,
+		source: source,
+		rates:  make(map[string]decimal.Decimal, len(rates)+1),
+	}
+
+	for currency, rate := range rates {
+		if !rate.IsPositive() {
+			return nil, fmt.Errorf("fx rate for %s must be positive", currency)
+		}
+		provider.rates[currency] = rate
+	}
+	provider.rates[base] = decimal.NewFromInt(1)
+
+	return provider, nil
+}
+
+// LoadStaticProvider reads a YAML or JSON rate table file
+func LoadStaticProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fx rates %s: %w", path, err)
+	}
+
+	var table rateTable
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&table)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&table)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse fx rates %s: %w", path, err)
+	}
+
+	if len(table.Base) != 3 {
+		return nil, fmt.Errorf("fx rates %s: base must be a 3 letter currency code", path)
+	}
+
+	rates := make(map[string]decimal.Decimal, len(table.Rates))
+	for currency, value := range table.Rates {
+		rate, err := decimal.NewFromString(value)
+		if err != nil {
+			return nil, fmt.Errorf("fx rates %s: invalid rate for %s: %w", path, currency, err)
+		}
+		rates[currency] = rate
+	}
+
+	provider, err := NewStaticProvider(table.Base, rates, table.AsOf, "static:"+filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("fx rates %s: %w", path, err)
+	}
+	return provider, nil
+}
+
+// Rate returns the rate converting from into to
+func (p *StaticProvider) Rate(ctx context.Context, from, to string) (Rate, error) {
+	rate := Rate{From: from, To: to, Source: p.source, AsOf: p.asOf}
+
+	if from == to {
+		rate.Value = decimal.NewFromInt(1)
+		return rate, nil
+	}
+
+	fromRate, ok := p.rates[from]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s", ErrRateNotFound, from)
+	}
+	toRate, ok := p.rates[to]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s", ErrRateNotFound, to)
+	}
+
+	switch {
+	case from == p.base:
+		rate.Value = toRate
+	default:
+		rate.Value = toRate.DivRound(fromRate, crossRatePrecision)
+	}
+
+	return rate, nil
+}
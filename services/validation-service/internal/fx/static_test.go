@@ -0,0 +1,89 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rateFile = `
+base: USD
+as_of: 2024-01-02T00:00:00Z
+rates:
+  EUR: "0.8"
+  JPY: "150"
+`
+
+func loadTestProvider(t *testing.T) *StaticProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(rateFile), 0o644))
+
+	provider, err := LoadStaticProvider(path)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestStaticProvider_Rate(t *testing.T) {
+	provider := loadTestProvider(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"USD", "USD", "1"},
+		{"USD", "JPY", "150"},
+		{"JPY", "USD", "0.006666666667"},
+		{"EUR", "USD", "1.25"},
+		{"EUR", "JPY", "187.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			rate, err := provider.Rate(ctx, tt.from, tt.to)
+			require.NoError(t, err)
+			assert.True(t, rate.Value.Equal(decimal.RequireFromString(tt.want)), "got %s", rate.Value)
+			assert.Equal(t, "static:rates.yaml", rate.Source)
+			assert.Equal(t, 2024, rate.AsOf.Year())
+		})
+	}
+}
+
+func TestStaticProvider_Rate_Unknown(t *testing.T) {
+	provider := loadTestProvider(t)
+
+	_, err := provider.Rate(context.Background(), "GBP", "USD")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestRate_Convert(t *testing.T) {
+	rate := Rate{From: "EUR", To: "USD", Value: decimal.RequireFromString("1.25")}
+
+	assert.Equal(t, "125", rate.Convert(decimal.NewFromInt(100)).String())
+}
+
+func TestLoadStaticProvider_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"no-base.yaml":    "rates:\n  EUR: \"0.9\"\n",
+		"bad-rate.yaml":   "base: USD\nrates:\n  EUR: abc\n",
+		"zero-rate.json":  `{"base": "USD", "rates": {"EUR": "0"}}`,
+		"bad-syntax.json": `{"base": `,
+		"unknown.yaml":    "base: USD\nrate:\n  EUR: \"0.9\"\n",
+		"unknown.json":    `{"base": "USD", "rate": {"EUR": "0.9"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+			_, err := LoadStaticProvider(path)
+			assert.Error(t, err)
+		})
+	}
+}
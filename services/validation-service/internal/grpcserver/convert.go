@@ -0,0 +1,128 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gtrs/validation-service/internal/models"
+	validationv1 "github.com/gtrs/validation-service/internal/pb/validation/v1"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toModelRequest converts a protobuf request, enforcing the same required fields
+// as the REST binding
+func toModelRequest(req *validationv1.ValidationRequest) (*models.ValidationRequest, error) {
+	if req == nil {
+		return nil, errors.New("request is required")
+	}
+
+	switch {
+	case req.GetTransactionId() == "":
+		return nil, errors.New("transaction_id is required")
+	case req.GetType() == "":
+		return nil, errors.New("type is required")
+	case len(req.GetCurrency()) != 3:
+		return nil, errors.New("currency must be a 3 letter code")
+	case req.GetCounterparty().GetId() == "":
+		return nil, errors.New("counterparty.id is required")
+	case req.GetCounterparty().GetName() == "":
+		return nil, errors.New("counterparty.name is required")
+	case req.GetCounterparty().GetType() == "":
+		return nil, errors.New("counterparty.type is required")
+	}
+
+	amount, err := models.ParseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.ValidationRequest{
+		TransactionID: req.GetTransactionId(),
+		Type:          req.GetType(),
+		Amount:        amount,
+		Currency:      req.GetCurrency(),
+		Counterparty: models.Counterparty{
+			ID:   req.GetCounterparty().GetId(),
+			Name: req.GetCounterparty().GetName(),
+			Type: req.GetCounterparty().GetType(),
+		},
+	}
+	if req.GetMetadata() != nil {
+		request.Metadata = req.GetMetadata().AsMap()
+	}
+	if req.GetTimestamp() != nil {
+		request.Timestamp = req.GetTimestamp().AsTime()
+	}
+
+	return request, nil
+}
+
+// fromModelResult converts a validation result to its protobuf form
+func fromModelResult(result *models.ValidationResult) (*validationv1.ValidationResult, error) {
+	metadata, err := toStruct(result.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("metadata: %w", err)
+	}
+
+	converted := &validationv1.ValidationResult{
+		Id:             result.ID,
+		TenantId:       result.TenantID,
+		TransactionId:  result.TransactionID,
+		CounterpartyId: result.CounterpartyID,
+		Amount:         result.Amount.String(),
+		Currency:       result.Currency,
+		Status:         string(result.Status),
+		Rules:          make([]*validationv1.RuleResult, 0, len(result.Rules)),
+		ErrorCode:      result.ErrorCode,
+		ErrorMessage:   result.ErrorMessage,
+		ProcessedAt:    timestamppb.New(result.ProcessedAt),
+		ProcessingTime: durationpb.New(result.ProcessingTime),
+		RiskScore:      result.RiskScore,
+		Metadata:       metadata,
+	}
+	if result.RequestedBy != nil {
+		converted.RequestedBy = &validationv1.Caller{Subject: result.RequestedBy.Subject, Method: result.RequestedBy.Method}
+	}
+
+	for _, rule := range result.Rules {
+		ruleMetadata, err := toStruct(rule.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s metadata: %w", rule.RuleID, err)
+		}
+
+		converted.Rules = append(converted.Rules, &validationv1.RuleResult{
+			RuleId:      rule.RuleID,
+			RuleName:    rule.RuleName,
+			Status:      rule.Status,
+			Message:     rule.Message,
+			ProcessedAt: timestamppb.New(rule.ProcessedAt),
+			Weight:      rule.Weight,
+			Metadata:    ruleMetadata,
+		})
+	}
+
+	return converted, nil
+}
+
+// toStruct converts free-form metadata through its JSON form, so values such as
+// structs and timestamps are encoded exactly as the REST API encodes them
+func toStruct(metadata map[string]interface{}) (*structpb.Struct, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := &structpb.Struct{}
+	if err := converted.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
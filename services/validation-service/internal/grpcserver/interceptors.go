@@ -0,0 +1,384 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/middleware"
+	validationv1 "github.com/gtrs/validation-service/internal/pb/validation/v1"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+// loggingUnaryInterceptor logs each unary call like the HTTP request logger
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	logrus.WithFields(logrus.Fields{
+		"method":  info.FullMethod,
+		"code":    status.Code(err).String(),
+		"latency": time.Since(start),
+	}).Info("gRPC request")
+
+	return resp, err
+}
+
+// loggingStreamInterceptor logs each stream when it ends
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+
+	logrus.WithFields(logrus.Fields{
+		"method":  info.FullMethod,
+		"code":    status.Code(err).String(),
+		"latency": time.Since(start),
+	}).Info("gRPC stream")
+
+	return err
+}
+
+// recoveryUnaryInterceptor turns a panic in a handler into an Internal error
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.WithFields(logrus.Fields{
+				"method": info.FullMethod,
+				"panic":  recovered,
+			}).Error("gRPC handler panicked")
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// recoveryStreamInterceptor turns a panic in a stream handler into an Internal error
+func recoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.WithFields(logrus.Fields{
+				"method": info.FullMethod,
+				"panic":  recovered,
+			}).Error("gRPC stream handler panicked")
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// healthServicePrefix marks the standard health service, which is open to probes
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// methodPermissions is the permission each ValidationService method requires
+var methodPermissions = map[string]auth.Permission{
+	validationv1.ValidationService_Validate_FullMethodName:       auth.PermissionCreateValidations,
+	validationv1.ValidationService_ValidateStream_FullMethodName: auth.PermissionCreateValidations,
+	validationv1.ValidationService_GetResult_FullMethodName:      auth.PermissionReadValidations,
+}
+
+// authenticate identifies the caller from "x-api-key" or "authorization: Bearer" metadata,
+// or else from the client certificate of the connection
+func authenticate(ctx context.Context, authenticator *auth.Authenticator) (context.Context, error) {
+	var apiKey, bearer string
+	if values := metadata.ValueFromIncomingContext(ctx, "x-api-key"); len(values) > 0 {
+		apiKey = values[0]
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		bearer = middleware.BearerToken(values[0])
+	}
+
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+
+	identity, err := authenticator.AuthenticateTLS(apiKey, bearer, state)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.NewContext(ctx, identity), nil
+}
+
+// authorize checks that the caller in ctx holds the permission the method requires.
+// Methods without a listed permission are refused.
+func authorize(ctx context.Context, policy *auth.Policy, method string) error {
+	identity, _ := auth.FromContext(ctx)
+	permission, ok := methodPermissions[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "no permission defined for %s", method)
+	}
+	if err := policy.Authorize(identity, permission); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"caller":     identity.Subject,
+			"method":     method,
+			"permission": permission,
+		}).Warn("Authorization failed")
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// authUnaryInterceptor rejects unauthenticated and unauthorized calls and passes the
+// caller's identity on
+func authUnaryInterceptor(authenticator *auth.Authenticator, policy *auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, policy, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor rejects unauthenticated and unauthorized streams and passes the
+// caller's identity on
+func authStreamInterceptor(authenticator *auth.Authenticator, policy *auth.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+		if err := authorize(ctx, policy, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// selectTenant adds the call's tenant to ctx: the tenant the caller is bound to by its
+// token or policy, or else the one named by "x-tenant-id" metadata, which authenticated
+// callers may only use with the tenants:any permission
+func selectTenant(ctx context.Context, policy *auth.Policy) (context.Context, error) {
+	bound, anyTenant, requested := tenant.Default, true, ""
+	if identity, ok := auth.FromContext(ctx); ok && policy != nil {
+		bound, anyTenant = policy.TenantScope(identity)
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, tenant.MetadataKey); len(values) > 0 {
+		requested = values[0]
+	}
+
+	tenantID, err := tenant.Resolve(bound, requested, anyTenant)
+	if errors.Is(err, tenant.ErrTenantMismatch) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return tenant.NewContext(ctx, tenantID), nil
+}
+
+// tenantUnaryInterceptor selects the tenant of each call. It runs after authentication.
+func tenantUnaryInterceptor(policy *auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := selectTenant(ctx, policy)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// tenantStreamInterceptor selects the tenant of each stream. It runs after authentication.
+func tenantStreamInterceptor(policy *auth.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := selectTenant(ss.Context(), policy)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// RateLimit returns server options applying the limits to every call except health
+// checks. byIP limits by peer IP before authentication, so that calls with missing or
+// invalid credentials are limited too; byClient and the in-flight cap apply after
+// authentication. Any of them may be nil. A stream takes a token from the caller's
+// bucket for each message it receives and holds an in-flight slot until it ends.
+func RateLimit(byIP, byClient *ratelimit.Limiter, inFlight *ratelimit.InFlight) []grpc.ServerOption {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+				return handler(ctx, req)
+			}
+
+			err := takeToken(ctx, byClient, info.FullMethod, func(md metadata.MD) { _ = grpc.SetHeader(ctx, md) })
+			if err != nil {
+				return nil, err
+			}
+			release, err := acquireSlot(ctx, inFlight, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+				return handler(srv, ss)
+			}
+
+			release, err := acquireSlot(ss.Context(), inFlight, info.FullMethod)
+			if err != nil {
+				return err
+			}
+			defer release()
+			if byClient != nil {
+				ss = &limitedStream{ServerStream: ss, limiter: byClient, method: info.FullMethod}
+			}
+			return handler(srv, ss)
+		}),
+	}
+	if byIP != nil {
+		options = append(options, grpc.InTapHandle(ipTap(byIP)))
+	}
+	return options
+}
+
+// ipTap returns a tap handle taking a token from the peer IP's bucket for each call.
+// Taps run as the call arrives, before any interceptor, and cannot set response
+// metadata, so the retry delay is given in the status message.
+func ipTap(limiter *ratelimit.Limiter) tap.ServerInHandle {
+	return func(ctx context.Context, info *tap.Info) (context.Context, error) {
+		if strings.HasPrefix(info.FullMethodName, healthServicePrefix) {
+			return ctx, nil
+		}
+
+		client := peerIP(ctx)
+		if decision := limiter.Allow(client); !decision.Allowed {
+			logrus.WithFields(logrus.Fields{
+				"client": client,
+				"method": info.FullMethodName,
+				"limit":  decision.Limit,
+			}).Warn("Rate limit exceeded")
+
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit of %d requests exceeded for %s, retry after %ds",
+				decision.Limit, client, int(math.Ceil(decision.RetryAfter.Seconds())))
+		}
+		return ctx, nil
+	}
+}
+
+// takeToken takes a token from the caller's bucket. When none is left it passes the
+// retry delay in seconds as "retry-after" metadata to setRetryAfter and returns
+// ResourceExhausted.
+func takeToken(ctx context.Context, limiter *ratelimit.Limiter, method string, setRetryAfter func(metadata.MD)) error {
+	if limiter == nil {
+		return nil
+	}
+
+	client := peerIP(ctx)
+	if identity, ok := auth.FromContext(ctx); ok {
+		client = identity.Subject
+	}
+
+	decision := limiter.Allow(client)
+	if decision.Allowed {
+		return nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"client": client,
+		"method": method,
+		"limit":  decision.Limit,
+	}).Warn("Rate limit exceeded")
+
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	setRetryAfter(metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return status.Errorf(codes.ResourceExhausted, "rate limit of %d requests exceeded for %s", decision.Limit, client)
+}
+
+// acquireSlot takes an in-flight slot, returning ResourceExhausted with "retry-after"
+// metadata when the cap is reached. The returned function releases the slot.
+func acquireSlot(ctx context.Context, inFlight *ratelimit.InFlight, method string) (func(), error) {
+	if inFlight == nil {
+		return func() {}, nil
+	}
+	if !inFlight.Acquire() {
+		logrus.WithFields(logrus.Fields{
+			"method": method,
+			"limit":  inFlight.Limit(),
+		}).Warn("In-flight request limit reached")
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", "1"))
+		return nil, status.Errorf(codes.ResourceExhausted, "server is at its limit of %d concurrent requests", inFlight.Limit())
+	}
+	return inFlight.Release, nil
+}
+
+// limitedStream takes a token from the caller's bucket for each message it receives,
+// ending the stream with ResourceExhausted when none is left. The retry delay is sent
+// in the trailer, as the headers may already have been sent.
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *ratelimit.Limiter
+	method  string
+}
+
+func (s *limitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return takeToken(s.Context(), s.limiter, s.method, s.SetTrailer)
+}
+
+// peerIP returns the IP address of the calling peer, or "" when unknown
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
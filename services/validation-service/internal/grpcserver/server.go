@@ -0,0 +1,171 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	validationv1 "github.com/gtrs/validation-service/internal/pb/validation/v1"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the gRPC ValidationService on top of services.ValidationService
+type Server struct {
+	validationv1.UnimplementedValidationServiceServer
+
+	validationService *services.ValidationService
+}
+
+// NewServer creates a gRPC server exposing the validation service and the standard
+// gRPC health service. Incoming trace context is continued by the global tracer provider.
+// When the authenticator has credentials configured, every call except health checks
+// must carry an API key or bearer token whose roles grant the method's permission.
+// Calls act for the caller's bound tenant or the tenant in "x-tenant-id" metadata.
+// Extra options, such as transport credentials, are applied to the server.
+func NewServer(validationService *services.ValidationService, authenticator *auth.Authenticator, policy *auth.Policy, opts ...grpc.ServerOption) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{loggingUnaryInterceptor, recoveryUnaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{loggingStreamInterceptor, recoveryStreamInterceptor}
+	if authenticator != nil && authenticator.Enabled() {
+		unary = append(unary, authUnaryInterceptor(authenticator, policy))
+		stream = append(stream, authStreamInterceptor(authenticator, policy))
+	}
+	unary = append(unary, tenantUnaryInterceptor(policy))
+	stream = append(stream, tenantStreamInterceptor(policy))
+
+	server := grpc.NewServer(append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}, opts...)...)
+
+	validationv1.RegisterValidationServiceServer(server, &Server{validationService: validationService})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(validationv1.ValidationService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	return server
+}
+
+// idempotencyKeyMetadata is the request metadata key carrying an explicit idempotency key
+const idempotencyKeyMetadata = "idempotency-key"
+
+// Validate validates a single transaction. An "idempotency-key" metadata entry overrides
+// the transaction ID as the idempotency key.
+func (s *Server) Validate(ctx context.Context, req *validationv1.ValidateRequest) (*validationv1.ValidateResponse, error) {
+	var key string
+	if values := metadata.ValueFromIncomingContext(ctx, idempotencyKeyMetadata); len(values) > 0 {
+		key = values[0]
+	}
+
+	result, err := s.validate(ctx, req.GetRequest(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &validationv1.ValidateResponse{Result: result}, nil
+}
+
+// GetResult returns a stored validation result
+func (s *Server) GetResult(ctx context.Context, req *validationv1.GetResultRequest) (*validationv1.GetResultResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "validation ID is required")
+	}
+
+	result, err := s.validationService.GetValidationResult(ctx, req.GetId())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "validation result %s not found", req.GetId())
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("validation_id", req.GetId()).Error("Failed to retrieve validation result")
+		return nil, status.Errorf(codes.Internal, "failed to retrieve validation result: %v", err)
+	}
+
+	converted, err := fromModelResult(result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode validation result: %v", err)
+	}
+
+	return &validationv1.GetResultResponse{Result: converted}, nil
+}
+
+// ValidateStream validates requests as they arrive, answering each in order
+func (s *Server) ValidateStream(stream validationv1.ValidationService_ValidateStreamServer) error {
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &validationv1.ValidateStreamResponse{
+			Index:         index,
+			TransactionId: req.GetRequest().GetTransactionId(),
+		}
+
+		result, err := s.validate(stream.Context(), req.GetRequest(), "")
+		if err != nil {
+			st := status.Convert(err)
+			resp.Outcome = &validationv1.ValidateStreamResponse_Error{
+				Error: &validationv1.StreamError{Code: st.Code().String(), Message: st.Message()},
+			}
+		} else {
+			resp.Outcome = &validationv1.ValidateStreamResponse_Result{Result: result}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// validate converts, validates and converts back, returning gRPC status errors
+func (s *Server) validate(ctx context.Context, req *validationv1.ValidationRequest, idempotencyKey string) (*validationv1.ValidationResult, error) {
+	request, err := toModelRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+
+	result, _, err := s.validationService.ValidateTransactionWithKey(ctx, request, idempotencyKey)
+	if errors.Is(err, services.ErrInvalidRequest) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if errors.Is(err, services.ErrIdempotencyConflict) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, services.ErrIdempotencyInProgress) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil, status.Error(codes.Canceled, err.Error())
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Validation failed")
+		return nil, status.Errorf(codes.Internal, "validation processing failed: %v", err)
+	}
+
+	converted, err := fromModelResult(result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode validation result: %v", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"transaction_id": request.TransactionID,
+		"status":         result.Status,
+	}).Info("Transaction validation completed")
+
+	return converted, nil
+}
@@ -0,0 +1,409 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	validationv1 "github.com/gtrs/validation-service/internal/pb/validation/v1"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func setupTestClient(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	return setupTestClientWithAuth(t, nil, nil)
+}
+
+func setupTestClientWithAuth(t *testing.T, authenticator *auth.Authenticator, policy *auth.Policy, opts ...grpc.ServerOption) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(services.NewValidationService(repository.NewMemoryResultRepository()), authenticator, policy, opts...)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newTestRequest(id, amount string) *validationv1.ValidationRequest {
+	metadata, _ := structpb.NewStruct(map[string]interface{}{"channel": "ONLINE"})
+	return &validationv1.ValidationRequest{
+		TransactionId: id,
+		Type:          "PAYMENT",
+		Amount:        amount,
+		Currency:      "USD",
+		Counterparty:  &validationv1.Counterparty{Id: "cp-1", Name: "Example Corp", Type: "BUSINESS"},
+		Metadata:      metadata,
+	}
+}
+
+func TestServer_ValidateAndGetResult(t *testing.T) {
+	client := validationv1.NewValidationServiceClient(setupTestClient(t))
+	ctx := context.Background()
+
+	resp, err := client.Validate(ctx, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "1000.50")})
+	require.NoError(t, err)
+	assert.Equal(t, "PASSED", resp.GetResult().GetStatus())
+	assert.Equal(t, "txn-1", resp.GetResult().GetTransactionId())
+	assert.Len(t, resp.GetResult().GetRules(), 3)
+	assert.Contains(t, resp.GetResult().GetRules()[0].GetMessage(), "1000.50")
+
+	fetched, err := client.GetResult(ctx, &validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, resp.GetResult().GetId(), fetched.GetResult().GetId())
+	assert.Equal(t, "PASSED", fetched.GetResult().GetStatus())
+}
+
+func TestServer_Validate_Failed(t *testing.T) {
+	client := validationv1.NewValidationServiceClient(setupTestClient(t))
+
+	resp, err := client.Validate(context.Background(), &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "2000000")})
+	require.NoError(t, err)
+	assert.Equal(t, "FAILED", resp.GetResult().GetStatus())
+	assert.Equal(t, "VALIDATION_FAILED", resp.GetResult().GetErrorCode())
+	assert.Equal(t, float64(100), resp.GetResult().GetRiskScore())
+}
+
+func TestServer_Errors(t *testing.T) {
+	client := validationv1.NewValidationServiceClient(setupTestClient(t))
+	ctx := context.Background()
+
+	missingCurrency := newTestRequest("txn-1", "100")
+	missingCurrency.Currency = ""
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing request", func() error {
+			_, err := client.Validate(ctx, &validationv1.ValidateRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"missing currency", func() error {
+			_, err := client.Validate(ctx, &validationv1.ValidateRequest{Request: missingCurrency})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid amount", func() error {
+			_, err := client.Validate(ctx, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "ten")})
+			return err
+		}, codes.InvalidArgument},
+		{"non-positive amount", func() error {
+			_, err := client.Validate(ctx, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "0")})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown result", func() error {
+			_, err := client.GetResult(ctx, &validationv1.GetResultRequest{Id: "val-missing"})
+			return err
+		}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestServer_ValidateStream(t *testing.T) {
+	client := validationv1.NewValidationServiceClient(setupTestClient(t))
+
+	stream, err := client.ValidateStream(context.Background())
+	require.NoError(t, err)
+
+	requests := []*validationv1.ValidationRequest{
+		newTestRequest("txn-1", "100"),
+		newTestRequest("txn-2", "not-a-number"),
+		newTestRequest("txn-3", "5000000"),
+	}
+	for _, req := range requests {
+		require.NoError(t, stream.Send(&validationv1.ValidateStreamRequest{Request: req}))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var responses []*validationv1.ValidateStreamResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		responses = append(responses, resp)
+	}
+
+	require.Len(t, responses, 3)
+	for i, resp := range responses {
+		assert.Equal(t, int64(i), resp.GetIndex())
+		assert.Equal(t, requests[i].GetTransactionId(), resp.GetTransactionId())
+	}
+	assert.Equal(t, "PASSED", responses[0].GetResult().GetStatus())
+	assert.Equal(t, "InvalidArgument", responses[1].GetError().GetCode())
+	assert.Equal(t, "FAILED", responses[2].GetResult().GetStatus())
+}
+
+func TestServer_Health(t *testing.T) {
+	client := healthpb.NewHealthClient(setupTestClient(t))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: validationv1.ValidationService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	keys, err := auth.ParseAPIKeys("transaction-service:" + auth.HashAPIKey("secret-1") + ",analyst:" + auth.HashAPIKey("secret-3") +
+		",auditor:" + auth.HashAPIKey("secret-4"))
+	require.NoError(t, err)
+	authenticator, err := auth.New(auth.Config{APIKeys: keys})
+	require.NoError(t, err)
+	return authenticator
+}
+
+func newTestPolicy(t *testing.T) *auth.Policy {
+	t.Helper()
+	policy, err := auth.NewPolicy(nil, map[string][]string{
+		"transaction-service": {"validator"},
+		"analyst":             {"analyst"},
+		"auditor":             {"analyst", "all-tenants"},
+	})
+	require.NoError(t, err)
+	return policy
+}
+
+func TestServer_Authentication(t *testing.T) {
+	conn := setupTestClientWithAuth(t, newTestAuthenticator(t), newTestPolicy(t))
+	client := validationv1.NewValidationServiceClient(conn)
+	request := &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "100")}
+
+	_, err := client.Validate(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	wrongKey := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-2")
+	_, err = client.Validate(wrongKey, request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.ValidateStream(context.Background())
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+	resp, err := client.Validate(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, "transaction-service", resp.GetResult().GetRequestedBy().GetSubject())
+	assert.Equal(t, auth.MethodAPIKey, resp.GetResult().GetRequestedBy().GetMethod())
+
+	// Health checks stay open for load balancers and orchestrators
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_Authorization(t *testing.T) {
+	client := validationv1.NewValidationServiceClient(setupTestClientWithAuth(t, newTestAuthenticator(t), newTestPolicy(t)))
+	validator := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+	analyst := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-3")
+
+	resp, err := client.Validate(validator, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "100")})
+	require.NoError(t, err)
+
+	_, err = client.Validate(analyst, &validationv1.ValidateRequest{Request: newTestRequest("txn-2", "100")})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "missing permission validations:create")
+
+	stream, err := client.ValidateStream(analyst)
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	fetched, err := client.GetResult(analyst, &validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, resp.GetResult().GetId(), fetched.GetResult().GetId())
+}
+
+func TestServer_Tenants(t *testing.T) {
+	policy := newTestPolicy(t)
+	policy.BindTenant("transaction-service", "retail")
+	client := validationv1.NewValidationServiceClient(setupTestClientWithAuth(t, newTestAuthenticator(t), policy))
+	retail := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+	analyst := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-3")
+	auditor := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-4")
+
+	resp, err := client.Validate(retail, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "100")})
+	require.NoError(t, err)
+	assert.Equal(t, "retail", resp.GetResult().GetTenantId())
+
+	// Unbound callers act for the default tenant and cannot choose another
+	_, err = client.GetResult(analyst, &validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetResult(metadata.AppendToOutgoingContext(analyst, "x-tenant-id", "retail"),
+		&validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Callers with tenants:any choose a tenant with metadata and see only its results
+	_, err = client.GetResult(auditor, &validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	fetched, err := client.GetResult(metadata.AppendToOutgoingContext(auditor, "x-tenant-id", "retail"),
+		&validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, resp.GetResult().GetId(), fetched.GetResult().GetId())
+
+	// Bound callers cannot act for another tenant
+	_, err = client.Validate(metadata.AppendToOutgoingContext(retail, "x-tenant-id", "corporate"),
+		&validationv1.ValidateRequest{Request: newTestRequest("txn-2", "100")})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetResult(metadata.AppendToOutgoingContext(analyst, "x-tenant-id", "not a tenant"),
+		&validationv1.GetResultRequest{Id: resp.GetResult().GetId()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthenticate_ClientCertificate(t *testing.T) {
+	authenticator, err := auth.New(auth.Config{ClientCertificates: true})
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "transaction-service"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+
+	ctx, err = authenticate(ctx, authenticator)
+	require.NoError(t, err)
+	identity, ok := auth.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "cert:transaction-service", identity.Subject)
+	assert.Equal(t, auth.MethodClientCert, identity.Method)
+
+	_, err = authenticate(context.Background(), authenticator)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Quota{}, map[string]ratelimit.Quota{
+		"analyst": {Rate: 0.001, Burst: 1},
+	})
+	conn := setupTestClientWithAuth(t, newTestAuthenticator(t), newTestPolicy(t), RateLimit(nil, limiter, nil)...)
+	client := validationv1.NewValidationServiceClient(conn)
+	analyst := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-3")
+
+	_, err := client.GetResult(analyst, &validationv1.GetResultRequest{Id: "val-missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	var header metadata.MD
+	_, err = client.GetResult(analyst, &validationv1.GetResultRequest{Id: "val-missing"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1000"}, header.Get("retry-after"))
+
+	// Other callers and health checks are not limited
+	validator := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+	for i := 0; i < 3; i++ {
+		_, err = client.Validate(validator, &validationv1.ValidateRequest{Request: newTestRequest(fmt.Sprintf("txn-%d", i), "100")})
+		require.NoError(t, err)
+	}
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_RateLimit_StreamMessages(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Quota{}, map[string]ratelimit.Quota{
+		"transaction-service": {Rate: 0.001, Burst: 2},
+	})
+	conn := setupTestClientWithAuth(t, newTestAuthenticator(t), newTestPolicy(t), RateLimit(nil, limiter, nil)...)
+	client := validationv1.NewValidationServiceClient(conn)
+	validator := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+
+	// Each message on the stream takes a token, not only the stream itself
+	stream, err := client.ValidateStream(validator)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&validationv1.ValidateStreamRequest{Request: newTestRequest(fmt.Sprintf("txn-%d", i), "100")}))
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.NotNil(t, resp.GetResult())
+	}
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1000"}, stream.Trailer().Get("retry-after"))
+
+	// The bucket is shared with unary calls
+	_, err = client.Validate(validator, &validationv1.ValidateRequest{Request: newTestRequest("txn-unary", "100")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServer_RateLimitByIP(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Quota{Rate: 0.001, Burst: 2}, nil)
+	conn := setupTestClientWithAuth(t, newTestAuthenticator(t), newTestPolicy(t), RateLimit(limiter, nil, nil)...)
+	client := validationv1.NewValidationServiceClient(conn)
+
+	// Calls with invalid credentials use up the peer's tokens before authentication
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "wrong")
+	for i := 0; i < 2; i++ {
+		_, err := client.GetResult(invalid, &validationv1.GetResultRequest{Id: "val-missing"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	validator := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-1")
+	_, err := client.Validate(validator, &validationv1.ValidateRequest{Request: newTestRequest("txn-1", "100")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "retry after 1000s")
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_MaxInFlight(t *testing.T) {
+	inFlight := ratelimit.NewInFlight(1)
+	client := validationv1.NewValidationServiceClient(setupTestClientWithAuth(t, nil, nil, RateLimit(nil, nil, inFlight)...))
+
+	// An open stream holds the only slot
+	stream, err := client.ValidateStream(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&validationv1.ValidateStreamRequest{Request: newTestRequest("txn-1", "100")}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	_, err = client.Validate(context.Background(), &validationv1.ValidateRequest{Request: newTestRequest("txn-2", "100")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Eventually(t, func() bool {
+		_, err := client.Validate(context.Background(), &validationv1.ValidateRequest{Request: newTestRequest("txn-3", "100")})
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminHandler handles operational endpoints
+type AdminHandler struct {
+	validationService *services.ValidationService
+	ruleLoader        *services.RuleLoader // nil when rules are not loaded from files
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(validationService *services.ValidationService, ruleLoader *services.RuleLoader) *AdminHandler {
+	return &AdminHandler{
+		validationService: validationService,
+		ruleLoader:        ruleLoader,
+	}
+}
+
+// ReloadRules reloads the rule set from the rules directory, like SIGHUP. On error
+// the current rules are kept.
+func (h *AdminHandler) ReloadRules(c *gin.Context) {
+	if h.ruleLoader == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Rules are not loaded from files",
+			"details": "RULES_DIR is not set",
+		})
+		return
+	}
+
+	if err := h.ruleLoader.Reload(h.validationService); err != nil {
+		logrus.WithError(err).Error("Rule reload failed, keeping previous rule set")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Rule reload failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rules := h.validationService.ListRules(c.Request.Context())
+	logrus.WithField("count", len(rules)).Info("Rules reloaded via admin API")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Rules reloaded",
+		"count":   len(rules),
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAdminRouter(loader *services.RuleLoader) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	handler := NewAdminHandler(service, loader)
+
+	router := gin.New()
+	router.POST("/api/admin/rules/reload", handler.ReloadRules)
+	return router
+}
+
+func TestAdminHandler_ReloadRules(t *testing.T) {
+	dir := t.TempDir()
+	rules := []byte("rules:\n  - id: limit\n    name: Limit\n    type: AMOUNT_LIMIT\n    config:\n      max_amount: 100\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), rules, 0o600))
+	router := setupAdminRouter(services.NewRuleLoader(dir, ""))
+
+	req, _ := http.NewRequest("POST", "/api/admin/rules/reload", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message": "Rules reloaded", "count": 1}`, w.Body.String())
+
+	// A broken file is rejected and the loaded rules are kept
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte("rules: [{id: x}]"), 0o600))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAdminHandler_ReloadRules_NoRulesDir(t *testing.T) {
+	router := setupAdminRouter(nil)
+
+	req, _ := http.NewRequest("POST", "/api/admin/rules/reload", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
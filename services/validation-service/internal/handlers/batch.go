@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sirupsen/logrus"
+)
+
+// maxBatchLineSize bounds a single NDJSON line
+const maxBatchLineSize = 1 << 20
+
+var (
+	errEmptyBatch    = errors.New("batch contains no requests")
+	errBatchTooLarge = errors.New("batch too large")
+)
+
+// BatchHandler handles bulk validation endpoints
+type BatchHandler struct {
+	validationService *services.ValidationService
+	workers           int
+	maxItems          int
+}
+
+// NewBatchHandler creates a batch handler validating with the given number of
+// workers and accepting at most maxItems requests per batch
+func NewBatchHandler(validationService *services.ValidationService, workers, maxItems int) *BatchHandler {
+	return &BatchHandler{
+		validationService: validationService,
+		workers:           workers,
+		maxItems:          maxItems,
+	}
+}
+
+// ValidateBatch validates a JSON array or NDJSON stream of validation requests.
+// Requests that fail to bind are reported per item without failing the batch. Each
+// item counts as a request against the caller's rate limit.
+func (h *BatchHandler) ValidateBatch(c *gin.Context) {
+	inputs, err := decodeBatch(c, h.maxItems)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	if !chargeBatch(c, len(inputs)) {
+		return
+	}
+
+	result := h.validationService.ValidateBatch(c.Request.Context(), inputs, h.workers)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondBatchError maps batch decoding errors to HTTP responses
+func respondBatchError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errBatchTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	logrus.WithError(err).Error("Invalid batch request")
+	c.JSON(status, gin.H{
+		"error":   "Invalid batch request",
+		"details": err.Error(),
+	})
+}
+
+// chargeBatch takes a rate limit token for each item beyond the first, which the rate
+// limiter took for the request itself. A batch larger than the caller's burst could
+// never be accepted and gets 413; one the bucket cannot cover yet gets 429 with
+// Retry-After. It reports whether the batch may proceed.
+func chargeBatch(c *gin.Context, items int) bool {
+	decision := ratelimit.Charge(c.Request.Context(), items-1)
+	if decision.Limit == 0 {
+		return true
+	}
+
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	if decision.Allowed {
+		return true
+	}
+
+	if items > decision.Limit {
+		respondBatchError(c, fmt.Errorf("%w: %d requests exceed the rate limit burst of %d", errBatchTooLarge, items, decision.Limit))
+		return false
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"path":  c.Request.URL.Path,
+		"items": items,
+		"limit": decision.Limit,
+	}).Warn("Rate limit exceeded")
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":   "Too many requests",
+		"details": fmt.Sprintf("rate limit of %d requests cannot take a batch of %d yet", decision.Limit, items),
+	})
+	return false
+}
+
+// decodeBatch reads the request body as NDJSON when the content type says so and
+// as a JSON array otherwise
+func decodeBatch(c *gin.Context, maxItems int) ([]services.BatchInput, error) {
+	var (
+		inputs []services.BatchInput
+		err    error
+	)
+
+	switch c.ContentType() {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		inputs, err = decodeNDJSONBatch(c.Request.Body, maxItems)
+	default:
+		inputs, err = decodeArrayBatch(c.Request.Body, maxItems)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		return nil, errEmptyBatch
+	}
+	return inputs, nil
+}
+
+// decodeArrayBatch streams the elements of a JSON array. Malformed JSON fails the
+// whole batch; elements that are valid JSON but not valid requests fail individually.
+func decodeArrayBatch(body io.Reader, maxItems int) ([]services.BatchInput, error) {
+	decoder := json.NewDecoder(body)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("batch must be a JSON array of validation requests")
+	}
+
+	var inputs []services.BatchInput
+	for decoder.More() {
+		if len(inputs) == maxItems {
+			return nil, fmt.Errorf("%w: more than %d requests", errBatchTooLarge, maxItems)
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("read batch item %d: %w", len(inputs), err)
+		}
+		inputs = append(inputs, bindBatchItem(raw))
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+
+	return inputs, nil
+}
+
+// decodeNDJSONBatch reads one request per line, skipping blank lines. A line that is
+// not a valid request fails only that item.
+func decodeNDJSONBatch(body io.Reader, maxItems int) ([]services.BatchInput, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+
+	var inputs []services.BatchInput
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(inputs) == maxItems {
+			return nil, fmt.Errorf("%w: more than %d requests", errBatchTooLarge, maxItems)
+		}
+		inputs = append(inputs, bindBatchItem(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+
+	return inputs, nil
+}
+
+// bindBatchItem decodes and validates one request the same way the single
+// validation endpoint binds its body
+func bindBatchItem(data []byte) services.BatchInput {
+	var request models.ValidationRequest
+	if err := binding.JSON.BindBody(data, &request); err != nil {
+		return services.BatchInput{Err: err}
+	}
+	return services.BatchInput{Request: &request}
+}
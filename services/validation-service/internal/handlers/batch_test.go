@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/middleware"
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBatchRouter(maxItems int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	handler := NewBatchHandler(service, 4, maxItems)
+
+	router := gin.New()
+	router.POST("/api/validate/batch", handler.ValidateBatch)
+	return router
+}
+
+func batchItemJSON(id, amount, currency string) string {
+	return fmt.Sprintf(`{"transaction_id": %q, "type": "PAYMENT", "amount": %s, "currency": %q,
+		"counterparty": {"id": "cp-1", "name": "Example Corp", "type": "BUSINESS"}}`, id, amount, currency)
+}
+
+func postBatch(router *gin.Engine, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/api/validate/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchHandler_JSONArray(t *testing.T) {
+	router := setupBatchRouter(100)
+
+	body := "[" + strings.Join([]string{
+		batchItemJSON("txn-1", "100", "USD"),
+		batchItemJSON("txn-2", "2000000", "USD"),
+		`{"transaction_id": "txn-3", "amount": 100}`,
+		batchItemJSON("txn-4", "10.001", "USD"),
+	}, ",") + "]"
+
+	w := postBatch(router, "application/json", body)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result models.BatchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+	assert.Equal(t, models.BatchSummary{Total: 4, Passed: 1, Failed: 1, Errors: 2, ProcessingTime: result.Summary.ProcessingTime}, result.Summary)
+	require.Len(t, result.Results, 4)
+
+	for i, item := range result.Results {
+		assert.Equal(t, i, item.Index)
+	}
+	assert.Equal(t, models.ValidationStatusPassed, result.Results[0].Result.Status)
+	assert.Equal(t, models.ValidationStatusFailed, result.Results[1].Result.Status)
+	assert.Equal(t, "Invalid request format", result.Results[2].Error)
+	assert.Nil(t, result.Results[2].Result)
+	assert.Equal(t, "Invalid request", result.Results[3].Error)
+	assert.Equal(t, "txn-4", result.Results[3].TransactionID)
+}
+
+func TestBatchHandler_NDJSON(t *testing.T) {
+	router := setupBatchRouter(100)
+
+	lines := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		lines = append(lines, strings.ReplaceAll(batchItemJSON(fmt.Sprintf("txn-%d", i), "100", "EUR"), "\n", ""))
+	}
+	body := strings.Join(lines, "\n") + "\n\nnot json\n"
+
+	w := postBatch(router, "application/x-ndjson", body)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result models.BatchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 51, result.Summary.Total)
+	assert.Equal(t, 50, result.Summary.Passed)
+	assert.Equal(t, 1, result.Summary.Errors)
+
+	ids := make(map[string]bool)
+	for i, item := range result.Results[:50] {
+		assert.Equal(t, fmt.Sprintf("txn-%d", i), item.TransactionID)
+		ids[item.Result.ID] = true
+	}
+	assert.Len(t, ids, 50, "validation IDs must be unique")
+}
+
+func TestBatchHandler_Rejected(t *testing.T) {
+	router := setupBatchRouter(2)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"not an array", "application/json", batchItemJSON("txn-1", "100", "USD"), http.StatusBadRequest},
+		{"malformed array", "application/json", "[" + batchItemJSON("txn-1", "100", "USD") + ",", http.StatusBadRequest},
+		{"empty array", "application/json", "[]", http.StatusBadRequest},
+		{"empty stream", "application/x-ndjson", "\n\n", http.StatusBadRequest},
+		{"too many items", "application/json", "[{}, {}, {}]", http.StatusRequestEntityTooLarge},
+		{"too many lines", "application/x-ndjson", "{}\n{}\n{}\n", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postBatch(router, tt.contentType, tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), "Invalid batch request")
+		})
+	}
+}
+
+func TestBatchHandler_RateLimitPerItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	handler := NewBatchHandler(service, 4, 100)
+	limiter := ratelimit.New(ratelimit.Quota{Rate: 0.001, Burst: 5}, nil)
+
+	router := gin.New()
+	router.POST("/api/validate/batch", middleware.RateLimit(limiter), handler.ValidateBatch)
+
+	items := make([]string, 5)
+	for i := range items {
+		items[i] = batchItemJSON(fmt.Sprintf("txn-%d", i), "100", "USD")
+	}
+
+	// A batch larger than the burst could never be accepted
+	w := postBatch(router, "application/json", "["+strings.Join(append(items, items[0]), ",")+"]")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "rate limit burst of 5")
+
+	// Refused batches only take the request's token: four are left, too few for a
+	// batch of five, which leaves three
+	w = postBatch(router, "application/json", "["+strings.Join(items, ",")+"]")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// A batch of three uses up the rest of the bucket, so the next request is refused
+	w = postBatch(router, "application/json", "["+strings.Join(items[:3], ",")+"]")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = postBatch(router, "application/json", "["+items[0]+"]")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestJobHandler_RateLimitPerItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	runner := services.NewJobRunner(service, repository.NewMemoryJobRepository(), services.JobRunnerConfig{
+		Workers:   2,
+		QueueSize: 10,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, runner.Start(ctx))
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+
+	limiter := ratelimit.New(ratelimit.Quota{Rate: 0.001, Burst: 5}, nil)
+	router := gin.New()
+	router.Use(middleware.RateLimit(limiter))
+	router.POST("/api/jobs", NewJobHandler(runner, 100).SubmitJob)
+	router.POST("/api/validate/batch", NewBatchHandler(service, 4, 100).ValidateBatch)
+
+	items := make([]string, 5)
+	for i := range items {
+		items[i] = batchItemJSON(fmt.Sprintf("txn-%d", i), "100", "USD")
+	}
+
+	// A job takes as many tokens as the same batch would
+	req, _ := http.NewRequest("POST", "/api/jobs", strings.NewReader("["+strings.Join(items, ",")+"]"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	w = postBatch(router, "application/json", "["+items[0]+"]")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// JobHandler handles asynchronous validation job endpoints
+type JobHandler struct {
+	jobRunner *services.JobRunner
+	maxItems  int
+}
+
+// NewJobHandler creates a job handler accepting at most maxItems requests per job
+func NewJobHandler(jobRunner *services.JobRunner, maxItems int) *JobHandler {
+	return &JobHandler{
+		jobRunner: jobRunner,
+		maxItems:  maxItems,
+	}
+}
+
+// SubmitJob queues a batch for background validation and returns the job immediately.
+// The body has the same format as the batch endpoint; an optional callback URL is
+// taken from the callback_url query parameter or the X-Callback-URL header. Like a
+// batch, each item counts as a request against the caller's rate limit.
+func (h *JobHandler) SubmitJob(c *gin.Context) {
+	inputs, err := decodeBatch(c, h.maxItems)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+	if !chargeBatch(c, len(inputs)) {
+		return
+	}
+
+	callbackURL := c.Query("callback_url")
+	if callbackURL == "" {
+		callbackURL = c.GetHeader("X-Callback-URL")
+	}
+
+	job, err := h.jobRunner.Submit(c.Request.Context(), inputs, callbackURL)
+	switch {
+	case errors.Is(err, services.ErrInvalidCallbackURL):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid callback URL",
+			"details": err.Error(),
+		})
+		return
+	case errors.Is(err, services.ErrJobQueueFull):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Job queue is full",
+			"details": err.Error(),
+		})
+		return
+	case err != nil:
+		logrus.WithError(err).Error("Failed to submit validation job")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to submit job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", "/api/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetJob returns a job's status and progress
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobRunner.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJobResults returns the per-item results a job has produced so far
+func (h *JobHandler) GetJobResults(c *gin.Context) {
+	job, err := h.jobRunner.GetJobResults(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":    job.ID,
+		"status":    job.Status,
+		"total":     job.Total,
+		"processed": job.Processed,
+		"summary":   job.Summary,
+		"results":   job.Results,
+	})
+}
+
+// respondJobError maps job lookup errors to HTTP responses
+func respondJobError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":  "Job not found",
+			"job_id": c.Param("id"),
+		})
+		return
+	}
+
+	logrus.WithError(err).WithField("job_id", c.Param("id")).Error("Failed to retrieve validation job")
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Failed to retrieve job",
+		"details": err.Error(),
+	})
+}
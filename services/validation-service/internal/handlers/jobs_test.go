@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	runner := services.NewJobRunner(service, repository.NewMemoryJobRepository(), services.JobRunnerConfig{
+		Workers:   2,
+		QueueSize: 10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, runner.Start(ctx))
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+
+	handler := NewJobHandler(runner, 100)
+	router := gin.New()
+	router.POST("/api/jobs", handler.SubmitJob)
+	router.GET("/api/jobs/:id", handler.GetJob)
+	router.GET("/api/jobs/:id/results", handler.GetJobResults)
+	return router
+}
+
+func TestJobHandler_SubmitAndPoll(t *testing.T) {
+	router := setupJobRouter(t)
+
+	body := "[" + batchItemJSON("txn-1", "100", "USD") + "," + batchItemJSON("txn-2", "2000000", "USD") + "]"
+	req, _ := http.NewRequest("POST", "/api/jobs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var job models.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, "/api/jobs/"+job.ID, w.Header().Get("Location"))
+
+	require.Eventually(t, func() bool {
+		req, _ := http.NewRequest("GET", "/api/jobs/"+job.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job.Status == models.JobStatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, job.Summary.Passed)
+	assert.Equal(t, 1, job.Summary.Failed)
+
+	req, _ = http.NewRequest("GET", "/api/jobs/"+job.ID+"/results", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results struct {
+		Results []models.BatchItemResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results.Results, 2)
+	assert.Equal(t, "txn-2", results.Results[1].TransactionID)
+}
+
+func TestJobHandler_InvalidCallback(t *testing.T) {
+	router := setupJobRouter(t)
+
+	req, _ := http.NewRequest("POST", "/api/jobs?callback_url=not-a-url", strings.NewReader("["+batchItemJSON("txn-1", "100", "USD")+"]"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid callback URL")
+}
+
+func TestJobHandler_NotFound(t *testing.T) {
+	router := setupJobRouter(t)
+
+	for _, path := range []string{"/api/jobs/job-missing", "/api/jobs/job-missing/results"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RuleHandler handles validation rule management endpoints
+type RuleHandler struct {
+	validationService *services.ValidationService
+}
+
+// NewRuleHandler creates a new rule handler
+func NewRuleHandler(validationService *services.ValidationService) *RuleHandler {
+	return &RuleHandler{
+		validationService: validationService,
+	}
+}
+
+// ListRules returns all configured validation rules
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	rules := h.validationService.ListRules(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// GetRule returns a single validation rule
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	rule, err := h.validationService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule adds a new validation rule
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	var request models.RuleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.validationService.CreateRule(c.Request.Context(), request.ToRule())
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule replaces an existing validation rule
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	var request models.RuleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	id := c.Param("id")
+	if request.ID != "" && request.ID != id {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Rule ID in body does not match path",
+		})
+		return
+	}
+
+	rule, err := h.validationService.UpdateRule(c.Request.Context(), id, request.ToRule())
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// EnableRule enables a validation rule
+func (h *RuleHandler) EnableRule(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+// DisableRule disables a validation rule
+func (h *RuleHandler) DisableRule(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+// DeleteRule removes a validation rule
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	if err := h.validationService.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RuleHandler) setEnabled(c *gin.Context, enabled bool) {
+	rule, err := h.validationService.SetRuleEnabled(c.Request.Context(), c.Param("id"), enabled)
+	if err != nil {
+		respondRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// respondRuleError maps rule service errors to HTTP responses
+func respondRuleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Rule not found",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrRuleExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Rule already exists",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrRulesReadOnly):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Rules are read-only",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidRule):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid rule",
+			"details": err.Error(),
+		})
+	default:
+		logrus.WithError(err).Error("Rule operation failed")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Rule operation failed",
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRuleRouter(opts ...services.Option) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository(), opts...)
+	handler := NewRuleHandler(service)
+
+	router := gin.New()
+	router.GET("/api/rules", handler.ListRules)
+	router.POST("/api/rules", handler.CreateRule)
+	router.GET("/api/rules/:id", handler.GetRule)
+	router.PUT("/api/rules/:id", handler.UpdateRule)
+	router.DELETE("/api/rules/:id", handler.DeleteRule)
+	router.POST("/api/rules/:id/disable", handler.DisableRule)
+	return router
+}
+
+func TestRuleHandler_CreateRule(t *testing.T) {
+	router := setupRuleRouter()
+
+	body := []byte(`{"id": "big-limit", "name": "Big Limit", "type": "AMOUNT_LIMIT", "config": {"max_amount": 5000}}`)
+	req, _ := http.NewRequest("POST", "/api/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var rule models.ValidationRule
+	err := json.Unmarshal(w.Body.Bytes(), &rule)
+	assert.NoError(t, err)
+	assert.Equal(t, "big-limit", rule.ID)
+	assert.True(t, rule.Enabled)
+
+	// Creating the same rule again conflicts
+	req, _ = http.NewRequest("POST", "/api/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRuleHandler_CreateRule_InvalidConfig(t *testing.T) {
+	router := setupRuleRouter()
+
+	body := []byte(`{"id": "bad", "name": "Bad", "type": "CURRENCY_CHECK", "config": {"allowed_currencies": "USD"}}`)
+	req, _ := http.NewRequest("POST", "/api/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRuleHandler_DisableAndDeleteRule(t *testing.T) {
+	router := setupRuleRouter()
+
+	req, _ := http.NewRequest("POST", "/api/rules/amount-limit/disable", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var rule models.ValidationRule
+	err := json.Unmarshal(w.Body.Bytes(), &rule)
+	assert.NoError(t, err)
+	assert.False(t, rule.Enabled)
+
+	req, _ = http.NewRequest("DELETE", "/api/rules/amount-limit", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest("GET", "/api/rules/amount-limit", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRuleHandler_ReadOnlyRules(t *testing.T) {
+	router := setupRuleRouter(services.WithReadOnlyRules())
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"POST", "/api/rules", `{"id": "big-limit", "name": "Big Limit", "type": "AMOUNT_LIMIT", "config": {"max_amount": 5000}}`},
+		{"PUT", "/api/rules/amount-limit", `{"name": "Amount Limit", "type": "AMOUNT_LIMIT", "config": {"max_amount": 5000}}`},
+		{"POST", "/api/rules/amount-limit/disable", ""},
+		{"DELETE", "/api/rules/amount-limit", ""},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, r.path, bytes.NewReader([]byte(r.body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code, r.method+" "+r.path)
+		assert.Contains(t, w.Body.String(), "Rules are read-only")
+	}
+
+	// Reading still works
+	req, _ := http.NewRequest("GET", "/api/rules/amount-limit", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
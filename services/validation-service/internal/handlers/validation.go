@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
 	"github.com/gtrs/validation-service/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +29,15 @@ func NewValidationHandler(validationService *services.ValidationService) *Valida
 	}
 }
 
+// idempotencyKeyHeader optionally overrides the transaction ID as the idempotency key
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentReplayedHeader marks a response that repeats the result of an earlier request
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// statusClientClosedRequest is logged when the client disconnects before the response (nginx convention)
+const statusClientClosedRequest = 499
+
 // ValidateTransaction handles transaction validation requests
 func (h *ValidationHandler) ValidateTransaction(c *gin.Context) {
 	var request models.ValidationRequest
@@ -36,7 +52,26 @@ func (h *ValidationHandler) ValidateTransaction(c *gin.Context) {
 	}
 
 	// Validate the transaction
-	result, err := h.validationService.ValidateTransaction(&request)
+	result, replayed, err := h.validationService.ValidateTransactionWithKey(c.Request.Context(), &request, c.GetHeader(idempotencyKeyHeader))
+	if errors.Is(err, services.ErrInvalidRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrIdempotencyConflict) || errors.Is(err, services.ErrIdempotencyInProgress) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Idempotency conflict",
+			"details": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		logrus.WithField("transaction_id", request.TransactionID).Warn("Client cancelled validation request")
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
 	if err != nil {
 		logrus.WithError(err).Error("Validation failed")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -49,8 +84,12 @@ func (h *ValidationHandler) ValidateTransaction(c *gin.Context) {
 	logrus.WithFields(logrus.Fields{
 		"transaction_id": request.TransactionID,
 		"status":         result.Status,
+		"replayed":       replayed,
 	}).Info("Transaction validation completed")
 
+	if replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
 	c.JSON(http.StatusOK, result)
 }
 
@@ -65,15 +104,112 @@ func (h *ValidationHandler) GetValidationResult(c *gin.Context) {
 		return
 	}
 
-	result, err := h.validationService.GetValidationResult(validationID)
+	result, err := h.validationService.GetValidationResult(c.Request.Context(), validationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":         "Validation result not found",
+			"validation_id": validationID,
+		})
+		return
+	}
 	if err != nil {
 		logrus.WithError(err).WithField("validation_id", validationID).Error("Failed to retrieve validation result")
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Validation result not found",
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve validation result",
 			"details": err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
-}
\ No newline at end of file
+}
+
+// SearchValidationResults lists stored validation results matching the query parameters
+func (h *ValidationHandler) SearchValidationResults(c *gin.Context) {
+	query, err := parseResultQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid search query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.validationService.SearchResults(c.Request.Context(), query, c.Query("cursor"))
+	if errors.Is(err, services.ErrInvalidQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid search query",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to search validation results")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to search validation results",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// parseResultQuery reads search filters, sort and page size from the query string
+func parseResultQuery(c *gin.Context) (models.ResultQuery, error) {
+	query := models.ResultQuery{
+		Filter: models.ResultFilter{
+			TransactionID:  c.Query("transaction_id"),
+			Status:         models.ValidationStatus(strings.ToUpper(c.Query("status"))),
+			FailedRuleID:   c.Query("failed_rule_id"),
+			CounterpartyID: c.Query("counterparty_id"),
+			Currency:       strings.ToUpper(c.Query("currency")),
+		},
+		SortBy:     c.Query("sort"),
+		Descending: true,
+	}
+
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc":
+		query.Descending = false
+	case "desc":
+	default:
+		return query, fmt.Errorf("order must be asc or desc, got %q", order)
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("limit must be an integer, got %q", value)
+		}
+		query.Limit = limit
+	}
+
+	for name, target := range map[string]**models.Amount{
+		"min_amount": &query.Filter.MinAmount,
+		"max_amount": &query.Filter.MaxAmount,
+	} {
+		if value := c.Query(name); value != "" {
+			amount, err := models.ParseAmount(value)
+			if err != nil {
+				return query, fmt.Errorf("%s: %w", name, err)
+			}
+			*target = &amount
+		}
+	}
+
+	for name, target := range map[string]**time.Time{
+		"from": &query.Filter.From,
+		"to":   &query.Filter.To,
+	} {
+		if value := c.Query(name); value != "" {
+			at, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return query, fmt.Errorf("%s must be an RFC 3339 timestamp, got %q", name, value)
+			}
+			*target = &at
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupValidationRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	handler := NewValidationHandler(service)
+
+	router := gin.New()
+	router.POST("/api/validate", handler.ValidateTransaction)
+	router.GET("/api/validate/:id", handler.GetValidationResult)
+	return router
+}
+
+func TestValidationHandler_GetValidationResult(t *testing.T) {
+	router := setupValidationRouter()
+
+	body := []byte(`{
+		"transaction_id": "txn-123",
+		"type": "PAYMENT",
+		"amount": 1000.00,
+		"currency": "USD",
+		"counterparty": {"id": "cp-456", "name": "Example Corp", "type": "BUSINESS"}
+	}`)
+
+	req, _ := http.NewRequest("POST", "/api/validate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var created models.ValidationResult
+	err := json.Unmarshal(w.Body.Bytes(), &created)
+	assert.NoError(t, err)
+
+	req, _ = http.NewRequest("GET", "/api/validate/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var fetched models.ValidationResult
+	err = json.Unmarshal(w.Body.Bytes(), &fetched)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, fetched.ID)
+	assert.Equal(t, "txn-123", fetched.TransactionID)
+	assert.Equal(t, models.ValidationStatusPassed, fetched.Status)
+}
+
+func TestValidationHandler_GetValidationResult_NotFound(t *testing.T) {
+	router := setupValidationRouter()
+
+	req, _ := http.NewRequest("GET", "/api/validate/val-missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestValidationHandler_ValidateTransaction_AmountPrecision(t *testing.T) {
+	router := setupValidationRouter()
+
+	tests := []struct {
+		name   string
+		amount string
+		code   int
+	}{
+		{"string amount", `"1000.10"`, http.StatusOK},
+		{"USD cents", `0.30`, http.StatusOK},
+		{"too many decimals for USD", `10.001`, http.StatusBadRequest},
+		{"zero", `0`, http.StatusBadRequest},
+		{"negative", `"-5"`, http.StatusBadRequest},
+		{"not a number", `"ten"`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(`{
+				"transaction_id": "txn-precision",
+				"type": "PAYMENT",
+				"amount": ` + tt.amount + `,
+				"currency": "USD",
+				"counterparty": {"id": "cp-1", "name": "Example Corp", "type": "BUSINESS"}
+			}`)
+
+			req, _ := http.NewRequest("POST", "/api/validate", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestValidationHandler_ValidateTransaction_Idempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository(),
+		services.WithIdempotency(repository.NewMemoryIdempotencyRepository(time.Hour)))
+	handler := NewValidationHandler(service)
+	router := gin.New()
+	router.POST("/api/validate", handler.ValidateTransaction)
+
+	post := func(amount, key string) *httptest.ResponseRecorder {
+		body := []byte(`{
+			"transaction_id": "txn-retry",
+			"type": "PAYMENT",
+			"amount": ` + amount + `,
+			"currency": "USD",
+			"counterparty": {"id": "cp-456", "name": "Example Corp", "type": "BUSINESS"}
+		}`)
+		req, _ := http.NewRequest("POST", "/api/validate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post("1000.00", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+	retry := post("1000.00", "")
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	var original, replayed models.ValidationResult
+	assert.NoError(t, json.Unmarshal(first.Body.Bytes(), &original))
+	assert.NoError(t, json.Unmarshal(retry.Body.Bytes(), &replayed))
+	assert.Equal(t, original.ID, replayed.ID)
+
+	conflict := post("2000.00", "")
+	assert.Equal(t, http.StatusConflict, conflict.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(conflict.Body.Bytes(), &response))
+	assert.Equal(t, "Idempotency conflict", response["error"])
+
+	// A fresh Idempotency-Key validates again
+	keyed := post("2000.00", "key-1")
+	assert.Equal(t, http.StatusOK, keyed.Code)
+	assert.Empty(t, keyed.Header().Get("Idempotent-Replayed"))
+}
+
+func TestValidationHandler_SearchValidationResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewValidationService(repository.NewMemoryResultRepository())
+	handler := NewValidationHandler(service)
+	router := gin.New()
+	router.POST("/api/validate", handler.ValidateTransaction)
+	router.GET("/api/validate", handler.SearchValidationResults)
+
+	for _, body := range []string{
+		`{"transaction_id": "txn-s1", "type": "PAYMENT", "amount": 1000.00, "currency": "USD",
+		  "counterparty": {"id": "cp-search", "name": "Example Corp", "type": "BUSINESS"}}`,
+		`{"transaction_id": "txn-s2", "type": "PAYMENT", "amount": 2000000.00, "currency": "USD",
+		  "counterparty": {"id": "cp-search", "name": "Example Corp", "type": "BUSINESS"}}`,
+		`{"transaction_id": "txn-s3", "type": "PAYMENT", "amount": 10.00, "currency": "USD",
+		  "counterparty": {"id": "cp-other", "name": "Other Corp", "type": "BUSINESS"}}`,
+	} {
+		req, _ := http.NewRequest("POST", "/api/validate", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	search := func(query string) (*httptest.ResponseRecorder, models.ResultPage) {
+		req, _ := http.NewRequest("GET", "/api/validate?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var page models.ResultPage
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		}
+		return w, page
+	}
+
+	w, page := search("counterparty_id=cp-search&status=failed")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, page.Results, 1) {
+		assert.Equal(t, "txn-s2", page.Results[0].TransactionID)
+		assert.Equal(t, "cp-search", page.Results[0].CounterpartyID)
+	}
+
+	w, page = search("min_amount=100&sort=amount&order=asc&limit=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, page.Results, 1) {
+		assert.Equal(t, "txn-s1", page.Results[0].TransactionID)
+	}
+	assert.NotEmpty(t, page.NextCursor)
+
+	w, page = search("min_amount=100&sort=amount&order=asc&limit=1&cursor=" + page.NextCursor)
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, page.Results, 1) {
+		assert.Equal(t, "txn-s2", page.Results[0].TransactionID)
+	}
+	assert.Empty(t, page.NextCursor)
+
+	for _, query := range []string{"from=yesterday", "min_amount=abc", "order=sideways", "limit=x", "sort=name"} {
+		w, _ := search(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestValidationHandler_ValidateTransaction_ClientCancelled(t *testing.T) {
+	router := setupValidationRouter()
+
+	body := []byte(`{
+		"transaction_id": "txn-gone",
+		"type": "PAYMENT",
+		"amount": 100.00,
+		"currency": "USD",
+		"counterparty": {"id": "cp-456", "name": "Example Corp", "type": "BUSINESS"}
+	}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/api/validate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, statusClientClosedRequest, w.Code)
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors exposed on /metrics. It implements
+// services.MetricsRecorder.
+type Metrics struct {
+	registry           *prometheus.Registry
+	requestDuration    *prometheus.HistogramVec
+	validations        *prometheus.CounterVec
+	validationDuration prometheus.Histogram
+	ruleEvaluations    *prometheus.CounterVec
+	ruleDuration       *prometheus.HistogramVec
+	ruleSetVersion     prometheus.Gauge
+}
+
+// New creates the collectors in their own registry, together with the Go runtime
+// and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status_code"}),
+		validations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "validations_total",
+			Help: "Completed validations by overall status.",
+		}, []string{"status"}),
+		validationDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "validation_duration_seconds",
+			Help:    "Time to evaluate all rules for a transaction.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		ruleEvaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rule_evaluations_total",
+			Help: "Rule results by rule and status (PASSED, FAILED, FLAGGED, SKIPPED, TIMEOUT, ERROR).",
+		}, []string{"rule_id", "status"}),
+		ruleDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "rule_evaluation_duration_seconds",
+			Help:    "Rule evaluation latency by rule.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"rule_id"}),
+		ruleSetVersion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "rule_set_version",
+			Help: "Version of the active rule set, incremented on every rule change or reload.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestDuration,
+		m.validations,
+		m.validationDuration,
+		m.ruleEvaluations,
+		m.ruleDuration,
+		m.ruleSetVersion,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records the latency of a request to a route
+func (m *Metrics) ObserveHTTPRequest(method, route string, statusCode int, duration time.Duration) {
+	m.requestDuration.WithLabelValues(method, route, strconv.Itoa(statusCode)).Observe(duration.Seconds())
+}
+
+// ObserveValidation records a completed validation
+func (m *Metrics) ObserveValidation(status models.ValidationStatus, duration time.Duration) {
+	m.validations.WithLabelValues(string(status)).Inc()
+	m.validationDuration.Observe(duration.Seconds())
+}
+
+// ObserveRuleResult counts one rule result
+func (m *Metrics) ObserveRuleResult(ruleID, status string) {
+	m.ruleEvaluations.WithLabelValues(ruleID, status).Inc()
+}
+
+// ObserveRuleDuration records how long a rule took to evaluate
+func (m *Metrics) ObserveRuleDuration(ruleID string, duration time.Duration) {
+	m.ruleDuration.WithLabelValues(ruleID).Observe(duration.Seconds())
+}
+
+// SetRuleSetVersion records the version of the active rule set
+func (m *Metrics) SetRuleSetVersion(version int64) {
+	m.ruleSetVersion.Set(float64(version))
+}
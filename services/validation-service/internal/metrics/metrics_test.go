@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Recording(t *testing.T) {
+	m := New()
+
+	m.ObserveValidation(models.ValidationStatusFailed, 3*time.Millisecond)
+	m.ObserveValidation(models.ValidationStatusFailed, 5*time.Millisecond)
+	m.ObserveValidation(models.ValidationStatusPassed, time.Millisecond)
+	m.ObserveRuleResult("amount-limit", "FAILED")
+	m.ObserveRuleResult("amount-limit", "PASSED")
+	m.ObserveRuleDuration("amount-limit", time.Millisecond)
+	m.SetRuleSetVersion(4)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.validations.WithLabelValues("FAILED")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.validations.WithLabelValues("PASSED")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ruleEvaluations.WithLabelValues("amount-limit", "FAILED")))
+	assert.Equal(t, float64(4), testutil.ToFloat64(m.ruleSetVersion))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.ruleDuration))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveHTTPRequest("POST", "/api/validate", 200, 10*time.Millisecond)
+	m.ObserveRuleResult("currency-check", "PASSED")
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := recorder.Body.String()
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="POST",route="/api/validate",status_code="200"} 1`)
+	assert.Contains(t, body, `rule_evaluations_total{rule_id="currency-check",status="PASSED"} 1`)
+	assert.True(t, strings.Contains(body, "go_goroutines"), "runtime metrics are exported")
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gtrs/validation-service/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// IdentityKey is the gin.Context key holding the caller's *auth.Identity
+const IdentityKey = "identity"
+
+// Authenticate returns a gin.HandlerFunc that requires an X-API-Key header, an
+// Authorization bearer token or, when accepted, a verified TLS client certificate. The
+// caller's identity is stored in the gin.Context and in the request context.
+func Authenticate(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := authenticator.AuthenticateTLS(c.GetHeader("X-API-Key"), BearerToken(c.GetHeader("Authorization")), c.Request.TLS)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"client_ip": c.ClientIP(),
+				"path":      c.Request.URL.Path,
+				"error":     err.Error(),
+			}).Warn("Authentication failed")
+
+			c.Header("WWW-Authenticate", `Bearer realm="validation-service"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.Set(IdentityKey, identity)
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), identity))
+		c.Next()
+	}
+}
+
+// Authorize returns a gin.HandlerFunc that requires the authenticated caller to hold a
+// role granting permission. It must run after Authenticate.
+func Authorize(policy *auth.Policy, permission auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, _ := c.MustGet(IdentityKey).(*auth.Identity)
+		if err := policy.Authorize(identity, permission); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"caller":     identity.Subject,
+				"path":       c.Request.URL.Path,
+				"permission": permission,
+			}).Warn("Authorization failed")
+
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":      "Forbidden",
+				"details":    err.Error(),
+				"permission": permission,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value
+func BearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -38,6 +38,8 @@ func CORS() gin.HandlerFunc {
 			"Accept-Encoding",
 			"X-CSRF-Token",
 			"Authorization",
+			"X-API-Key",
+			"X-Tenant-ID",
 			"Accept",
 			"Cache-Control",
 			"X-Requested-With",
@@ -45,6 +47,10 @@ func CORS() gin.HandlerFunc {
 		ExposeHeaders: []string{
 			"Content-Length",
 			"Content-Type",
+			"Retry-After",
+			"X-RateLimit-Limit",
+			"X-RateLimit-Remaining",
+			"X-RateLimit-Reset",
 		},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
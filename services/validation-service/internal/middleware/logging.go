@@ -3,6 +3,8 @@ package middleware
 import (
 	"time"
 
+	"github.com/gtrs/validation-service/internal/auth"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -10,6 +12,12 @@ import (
 // Logger returns a gin.HandlerFunc for logging HTTP requests
 func Logger() gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		var caller string
+		if identity, ok := param.Keys[IdentityKey].(*auth.Identity); ok {
+			caller = identity.Subject
+		}
+		tenantID, _ := param.Keys[TenantKey].(string)
+
 		logrus.WithFields(logrus.Fields{
 			"client_ip":   param.ClientIP,
 			"timestamp":   param.TimeStamp.Format(time.RFC3339),
@@ -19,6 +27,8 @@ func Logger() gin.HandlerFunc {
 			"status_code": param.StatusCode,
 			"latency":     param.Latency,
 			"user_agent":  param.Request.UserAgent(),
+			"caller":      caller,
+			"tenant_id":   tenantID,
 			"error":       param.ErrorMessage,
 		}).Info("HTTP Request")
 
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gtrs/validation-service/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics returns a gin.HandlerFunc recording request latency by route. Requests that
+// match no route share the "unmatched" label so that arbitrary paths cannot create series.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
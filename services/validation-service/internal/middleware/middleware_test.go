@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	keys, err := auth.ParseAPIKeys("transaction-service:" + auth.HashAPIKey("secret-1") +
+		",retail-gateway:" + auth.HashAPIKey("secret-2") +
+		",auditor:" + auth.HashAPIKey("secret-3"))
+	require.NoError(t, err)
+	authenticator, err := auth.New(auth.Config{APIKeys: keys})
+	require.NoError(t, err)
+	return authenticator
+}
+
+func newTestPolicy(t *testing.T) *auth.Policy {
+	t.Helper()
+	policy, err := auth.NewPolicy(nil, map[string][]string{
+		"transaction-service": {"validator"},
+		"retail-gateway":      {"validator"},
+		"auditor":             {"analyst", "all-tenants"},
+	})
+	require.NoError(t, err)
+	policy.BindTenant("retail-gateway", "retail")
+	return policy
+}
+
+// setupTenantRouter serves the tenant each request was resolved to
+func setupTenantRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(newTestAuthenticator(t)), Tenant(newTestPolicy(t)))
+	router.GET("/tenant", func(c *gin.Context) {
+		c.String(http.StatusOK, tenant.FromContext(c.Request.Context()))
+	})
+	return router
+}
+
+func get(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	return getFrom(router, path, "192.0.2.1:40000", headers)
+}
+
+// getFrom sends a GET request from the peer address remoteAddr
+func getFrom(router *gin.Engine, path, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(newTestAuthenticator(t)))
+	router.GET("/whoami", func(c *gin.Context) {
+		identity, ok := auth.FromContext(c.Request.Context())
+		require.True(t, ok)
+		c.String(http.StatusOK, identity.Subject)
+	})
+
+	w := get(router, "/whoami", map[string]string{"X-API-Key": "secret-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "transaction-service", w.Body.String())
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"missing credentials", nil},
+		{"invalid API key", map[string]string{"X-API-Key": "wrong"}},
+		{"bearer token not accepted", map[string]string{"Authorization": "Bearer token"}},
+		{"not a bearer token", map[string]string{"Authorization": "Basic c2VjcmV0LTE="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(router, "/whoami", tt.headers)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, `Bearer realm="validation-service"`, w.Header().Get("WWW-Authenticate"))
+			assert.Contains(t, w.Body.String(), "Unauthorized")
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(newTestAuthenticator(t)), Authorize(newTestPolicy(t), auth.PermissionCreateValidations))
+	router.GET("/validate", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := get(router, "/validate", map[string]string{"X-API-Key": "secret-1"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// The auditor may read results but not create them
+	w = get(router, "/validate", map[string]string{"X-API-Key": "secret-3"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"permission":"validations:create"`)
+
+	w = get(router, "/validate", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "authentication runs first")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	limiter := ratelimit.New(ratelimit.Quota{Rate: 0.001, Burst: 1}, nil)
+	router.Use(Authenticate(newTestAuthenticator(t)), RateLimit(limiter))
+	router.GET("/limited", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := get(router, "/limited", map[string]string{"X-API-Key": "secret-1"})
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = get(router, "/limited", map[string]string{"X-API-Key": "secret-1"})
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "transaction-service")
+
+	// Callers have buckets of their own, whatever address they connect from
+	w = get(router, "/limited", map[string]string{"X-API-Key": "secret-2"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.1"}))
+	limiter := ratelimit.New(ratelimit.Quota{Rate: 0.001, Burst: 1}, nil)
+	router.Use(RateLimitByIP(limiter), Authenticate(newTestAuthenticator(t)))
+	router.GET("/limited", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	t.Run("requests failing authentication are limited", func(t *testing.T) {
+		w := getFrom(router, "/limited", "192.0.2.10:40000", map[string]string{"X-API-Key": "wrong"})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		w = getFrom(router, "/limited", "192.0.2.10:40001", map[string]string{"X-API-Key": "secret-1"})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("X-Forwarded-For from an untrusted peer is ignored", func(t *testing.T) {
+		w := getFrom(router, "/limited", "192.0.2.20:40000", map[string]string{"X-API-Key": "secret-1", "X-Forwarded-For": "198.51.100.1"})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = getFrom(router, "/limited", "192.0.2.20:40000", map[string]string{"X-API-Key": "secret-1", "X-Forwarded-For": "198.51.100.2"})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, "a spoofed header does not pick a fresh bucket")
+		assert.Contains(t, w.Body.String(), "192.0.2.20")
+	})
+
+	t.Run("X-Forwarded-For from a trusted proxy names the client", func(t *testing.T) {
+		w := getFrom(router, "/limited", "10.0.0.1:40000", map[string]string{"X-API-Key": "secret-1", "X-Forwarded-For": "198.51.100.1"})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = getFrom(router, "/limited", "10.0.0.1:40000", map[string]string{"X-API-Key": "secret-1", "X-Forwarded-For": "198.51.100.2"})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = getFrom(router, "/limited", "10.0.0.1:40000", map[string]string{"X-API-Key": "secret-1", "X-Forwarded-For": "198.51.100.1"})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
+
+func TestTenant(t *testing.T) {
+	router := setupTenantRouter(t)
+
+	tests := []struct {
+		name     string
+		key      string
+		tenantID string
+		status   int
+		expected string
+	}{
+		{"unbound caller uses the default tenant", "secret-1", "", http.StatusOK, tenant.Default},
+		{"unbound caller cannot choose a tenant", "secret-1", "retail", http.StatusForbidden, ""},
+		{"bound caller uses its tenant", "secret-2", "", http.StatusOK, "retail"},
+		{"bound caller naming its tenant", "secret-2", "retail", http.StatusOK, "retail"},
+		{"bound caller naming another tenant", "secret-2", "corporate", http.StatusForbidden, ""},
+		{"tenants:any caller chooses a tenant", "secret-3", "corporate", http.StatusOK, "corporate"},
+		{"invalid tenant", "secret-3", "not a tenant", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"X-API-Key": tt.key}
+			if tt.tenantID != "" {
+				headers[tenant.Header] = tt.tenantID
+			}
+			w := get(router, "/tenant", headers)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.expected, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "Invalid tenant")
+			}
+		})
+	}
+}
+
+func TestTenant_Unauthenticated(t *testing.T) {
+	// Without authentication every request may choose its tenant
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tenant(newTestPolicy(t)))
+	router.GET("/tenant", func(c *gin.Context) {
+		c.String(http.StatusOK, tenant.FromContext(c.Request.Context()))
+	})
+
+	w := get(router, "/tenant", map[string]string{tenant.Header: "retail"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "retail", w.Body.String())
+}
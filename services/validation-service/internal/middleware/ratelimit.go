@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimit returns a gin.HandlerFunc that takes a token from the caller's bucket for
+// each request. Callers are identified by their authenticated subject, or by client
+// IP when the request is anonymous, so it must run after Authenticate. Limited
+// responses carry X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset;
+// requests over the quota get 429 with Retry-After. Handlers doing more than one
+// request's worth of work take the rest from the same bucket with ratelimit.Charge.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := c.ClientIP()
+		if value, ok := c.Get(IdentityKey); ok {
+			client = value.(*auth.Identity).Subject
+		}
+
+		if !limit(c, limiter, client) {
+			return
+		}
+		c.Request = c.Request.WithContext(ratelimit.NewContext(c.Request.Context(), limiter, client))
+		c.Next()
+	}
+}
+
+// RateLimitByIP returns a gin.HandlerFunc that takes a token from the client IP's
+// bucket for each request, like RateLimit. It runs before Authenticate, so that
+// requests with missing or invalid credentials are limited too.
+func RateLimitByIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit(c, limiter, c.ClientIP()) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// limit takes a token from the client's bucket and sets the rate limit headers. When
+// the bucket is empty it aborts the request with 429 and reports false.
+func limit(c *gin.Context, limiter *ratelimit.Limiter, client string) bool {
+	decision := limiter.Allow(client)
+	if decision.Limit == 0 {
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	if !decision.Allowed {
+		logrus.WithFields(logrus.Fields{
+			"client": client,
+			"path":   c.Request.URL.Path,
+			"limit":  decision.Limit,
+		}).Warn("Rate limit exceeded")
+
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":   "Too many requests",
+			"details": fmt.Sprintf("rate limit of %d requests exceeded for %s", decision.Limit, client),
+		})
+		return false
+	}
+	return true
+}
+
+// MaxInFlight returns a gin.HandlerFunc that refuses requests with 429 while the
+// in-flight cap is reached
+func MaxInFlight(inFlight *ratelimit.InFlight) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !inFlight.Acquire() {
+			logrus.WithFields(logrus.Fields{
+				"path":  c.Request.URL.Path,
+				"limit": inFlight.Limit(),
+			}).Warn("In-flight request limit reached")
+
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many requests",
+				"details": fmt.Sprintf("server is at its limit of %d concurrent requests", inFlight.Limit()),
+			})
+			return
+		}
+		defer inFlight.Release()
+
+		c.Next()
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds, as used by Retry-After
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TenantKey is the gin.Context key holding the request's tenant ID
+const TenantKey = "tenant"
+
+// Tenant returns a gin.HandlerFunc that selects the request's tenant from the caller's
+// token or policy binding, or else from the X-Tenant-ID header, which authenticated
+// callers may only use with the tenants:any permission. The tenant ID is stored in the
+// gin.Context and in the request context. It must run after Authenticate when
+// authentication is enabled.
+func Tenant(policy *auth.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bound, anyTenant := tenant.Default, true
+		if value, ok := c.Get(IdentityKey); ok {
+			bound, anyTenant = policy.TenantScope(value.(*auth.Identity))
+		}
+
+		tenantID, err := tenant.Resolve(bound, c.GetHeader(tenant.Header), anyTenant)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, tenant.ErrTenantMismatch) {
+				status = http.StatusForbidden
+				logrus.WithFields(logrus.Fields{
+					"client_ip": c.ClientIP(),
+					"path":      c.Request.URL.Path,
+					"tenant_id": bound,
+					"requested": c.GetHeader(tenant.Header),
+				}).Warn("Tenant mismatch")
+			}
+
+			c.AbortWithStatusJSON(status, gin.H{
+				"error":   "Invalid tenant",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.Set(TenantKey, tenantID)
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// BatchItemResult is the outcome of one request in a batch: either its validation
+// result or the reason it could not be validated
+type BatchItemResult struct {
+	Index         int               `json:"index"`
+	TransactionID string            `json:"transaction_id,omitempty"`
+	Result        *ValidationResult `json:"result,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	Details       string            `json:"details,omitempty"`
+}
+
+// BatchSummary counts the outcomes of a batch
+type BatchSummary struct {
+	Total          int           `json:"total"`
+	Passed         int           `json:"passed"`
+	Review         int           `json:"review"`
+	Failed         int           `json:"failed"`
+	Errors         int           `json:"errors"` // items that could not be validated or whose result is ERROR
+	ProcessingTime time.Duration `json:"processing_time"`
+}
+
+// BatchResult holds per-item results, in request order, and their summary
+type BatchResult struct {
+	Summary BatchSummary      `json:"summary"`
+	Results []BatchItemResult `json:"results"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// Caller identifies who requested a validation, for audit
+type Caller struct {
+	Subject string `json:"subject"` // API key name or token subject
+	Method  string `json:"method"`  // api_key, jwt or client_cert
+}
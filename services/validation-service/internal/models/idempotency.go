@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// IdempotencyRecord links an idempotency key to the request first made with it and,
+// once validation completes, to its result
+type IdempotencyRecord struct {
+	Key          string
+	Fingerprint  string // hash of the request payload
+	ValidationID string // empty while the first request is still being validated
+	CreatedAt    time.Time
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// JobStatus represents the processing state of an asynchronous validation job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "QUEUED"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusCompleted JobStatus = "COMPLETED"
+)
+
+// CallbackStatus represents the delivery state of a job's completion callback
+type CallbackStatus string
+
+const (
+	CallbackStatusPending   CallbackStatus = "PENDING"
+	CallbackStatusDelivered CallbackStatus = "DELIVERED"
+	CallbackStatusFailed    CallbackStatus = "FAILED"
+)
+
+// Job is an asynchronous batch validation. Items and Results are persisted with the
+// job but not included in its JSON representation; results are served separately.
+type Job struct {
+	ID               string         `json:"id"`
+	TenantID         string         `json:"tenant_id,omitempty"`
+	Status           JobStatus      `json:"status"`
+	Total            int            `json:"total"`
+	Processed        int            `json:"processed"`
+	Summary          BatchSummary   `json:"summary"`
+	CallbackURL      string         `json:"callback_url,omitempty"`
+	CallbackStatus   CallbackStatus `json:"callback_status,omitempty"`
+	CallbackAttempts int            `json:"callback_attempts,omitempty"`
+	CallbackError    string         `json:"callback_error,omitempty"`
+	RequestedBy      *Caller        `json:"requested_by,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	CompletedAt      *time.Time     `json:"completed_at,omitempty"`
+
+	Items   []JobItem         `json:"-"`
+	Results []BatchItemResult `json:"-"`
+}
+
+// JobItem is one submitted request, or the reason it could not be decoded
+type JobItem struct {
+	Request *ValidationRequest `json:"request,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// JobCallback is the body POSTed to a job's callback URL when it completes
+type JobCallback struct {
+	JobID   string            `json:"job_id"`
+	Status  JobStatus         `json:"status"`
+	Summary BatchSummary      `json:"summary"`
+	Results []BatchItemResult `json:"results"`
+}
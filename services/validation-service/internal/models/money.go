@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/shopspring/decimal"
+)
+
+// Limits on the amounts accepted from input. Exponent notation is refused as well, since
+// an exponent such as 1e10000000 makes every later comparison and format expensive.
+const (
+	MaxAmountIntegerDigits = 24
+	MaxAmountScale         = 18
+)
+
+var amountPattern = regexp.MustCompile(fmt.Sprintf(`^[+-]?[0-9]{1,%d}(\.[0-9]{1,%d})?$`, MaxAmountIntegerDigits, MaxAmountScale))
+
+// Amount is an exact decimal monetary amount. It decodes from a JSON number or
+// string without going through float64 and encodes as a JSON number.
+type Amount struct {
+	value decimal.Decimal
+}
+
+// NewAmount creates an amount from a decimal value
+func NewAmount(value decimal.Decimal) Amount {
+	return Amount{value: value}
+}
+
+// ParseAmount parses a decimal string such as "1000.50". Exponent notation and amounts
+// beyond MaxAmountIntegerDigits integer digits or MaxAmountScale decimals are refused.
+func ParseAmount(s string) (Amount, error) {
+	if !amountPattern.MatchString(s) {
+		return Amount{}, fmt.Errorf("invalid amount %q: must be a plain decimal with at most %d integer digits and %d decimals",
+			truncate(s, 40), MaxAmountIntegerDigits, MaxAmountScale)
+	}
+
+	value, err := decimal.NewFromString(s)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return Amount{value: value}, nil
+}
+
+// MustParseAmount is like ParseAmount but panics on error. Intended for constants and tests.
+func MustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// Decimal returns the underlying decimal value
+func (a Amount) Decimal() decimal.Decimal {
+	return a.value
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (a Amount) IsPositive() bool {
+	return a.value.IsPositive()
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1
+func (a Amount) Cmp(other Amount) int {
+	return a.value.Cmp(other.value)
+}
+
+// Add returns the sum of two amounts
+func (a Amount) Add(other Amount) Amount {
+	return Amount{value: a.value.Add(other.value)}
+}
+
+// Round rounds the amount half away from zero to the currency's minor units.
+// Currencies without a known minor unit are left unchanged.
+func (a Amount) Round(currency string) Amount {
+	units, ok := CurrencyMinorUnits(currency)
+	if !ok {
+		return a
+	}
+	return Amount{value: a.value.Round(int32(units))}
+}
+
+// String returns the exact decimal representation
+func (a Amount) String() string {
+	return a.value.String()
+}
+
+// Format renders the amount with the number of decimals used by the currency
+func (a Amount) Format(currency string) string {
+	if units, ok := CurrencyMinorUnits(currency); ok && -a.value.Exponent() <= int32(units) {
+		return a.value.StringFixed(int32(units))
+	}
+	return a.value.String()
+}
+
+// Float64 returns the nearest float64; only for contexts that cannot use decimals
+func (a Amount) Float64() float64 {
+	return a.value.InexactFloat64()
+}
+
+// FitsCurrency reports whether the amount has no more decimals than the currency's
+// minor unit allows. Currencies without a known minor unit are not checked.
+func (a Amount) FitsCurrency(currency string) bool {
+	units, ok := CurrencyMinorUnits(currency)
+	if !ok {
+		return true
+	}
+	return a.value.Equal(a.value.Truncate(int32(units)))
+}
+
+// MarshalJSON encodes the amount as a JSON number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.value.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or numeric string exactly
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	if len(data) == 0 {
+		return errors.New("amount must not be empty")
+	}
+
+	amount, err := ParseAmount(string(data))
+	if err != nil {
+		return err
+	}
+
+	*a = amount
+	return nil
+}
+
+// truncate shortens s for error messages
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmount_UnmarshalJSON_Lossless(t *testing.T) {
+	var values struct {
+		Number Amount `json:"number"`
+		String Amount `json:"string"`
+		Large  Amount `json:"large"`
+	}
+
+	err := json.Unmarshal([]byte(`{"number": 0.1, "string": "0.2", "large": 12345678901234567.89}`), &values)
+	require.NoError(t, err)
+
+	// 0.1 + 0.2 is exactly 0.3, unlike with float64
+	assert.Equal(t, 0, values.Number.Add(values.String).Cmp(MustParseAmount("0.3")))
+	assert.Equal(t, "12345678901234567.89", values.Large.String())
+}
+
+func TestAmount_UnmarshalJSON_Invalid(t *testing.T) {
+	var amount Amount
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &amount))
+	assert.Error(t, json.Unmarshal([]byte(`""`), &amount))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &amount))
+}
+
+func TestParseAmount_Limits(t *testing.T) {
+	for _, input := range []string{
+		"1e10000000",
+		"1E3",
+		"1.5e-2",
+		"1000000000000000000000000", // 25 integer digits
+		"0.1234567890123456789",     // 19 decimals
+		"1.",
+		".5",
+		"1_000",
+		strings.Repeat("9", 10000),
+	} {
+		_, err := ParseAmount(input)
+		assert.Error(t, err, input)
+
+		var amount Amount
+		assert.Error(t, json.Unmarshal([]byte(input), &amount), input)
+	}
+
+	for _, input := range []string{"999999999999999999999999", "0.123456789012345678", "-1.5", "+2"} {
+		_, err := ParseAmount(input)
+		assert.NoError(t, err, input)
+	}
+
+	_, err := ParseAmount(strings.Repeat("9", 10000))
+	assert.Less(t, len(err.Error()), 200, "the input is not echoed in full")
+}
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Amount `json:"amount"`
+	}{MustParseAmount("1000.50")})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 1000.50}`, string(data))
+}
+
+func TestAmount_FitsCurrency(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		fits     bool
+	}{
+		{"10.25", "USD", true},
+		{"10.250", "USD", true}, // trailing zeros do not count
+		{"10.255", "USD", false},
+		{"1000", "JPY", true},
+		{"1000.5", "JPY", false},
+		{"1.255", "BHD", true},
+		{"1.2555", "BHD", false},
+		{"1.23456", "XYZ", true}, // unknown currencies are not checked
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			assert.Equal(t, tt.fits, MustParseAmount(tt.amount).FitsCurrency(tt.currency))
+		})
+	}
+}
+
+func TestAmount_Format(t *testing.T) {
+	assert.Equal(t, "1000.00", MustParseAmount("1000").Format("USD"))
+	assert.Equal(t, "1000", MustParseAmount("1000").Format("JPY"))
+	assert.Equal(t, "1.500", MustParseAmount("1.5").Format("BHD"))
+	assert.Equal(t, "1.5", MustParseAmount("1.5").Format("XYZ"))
+}
+
+func TestValidationRequest_Validate(t *testing.T) {
+	request := &ValidationRequest{Amount: MustParseAmount("100.10"), Currency: "USD"}
+	assert.NoError(t, request.Validate())
+
+	request.Amount = MustParseAmount("100.1")
+	request.Currency = "JPY"
+	assert.Error(t, request.Validate())
+
+	request.Amount = Amount{}
+	assert.Error(t, request.Validate())
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Sort fields for result searches
+const (
+	ResultSortProcessedAt = "processed_at"
+	ResultSortRiskScore   = "risk_score"
+	ResultSortAmount      = "amount"
+)
+
+// ResultFilter selects validation results. Zero-valued fields do not filter.
+type ResultFilter struct {
+	TransactionID  string
+	Status         ValidationStatus
+	FailedRuleID   string // results in which this rule FAILED
+	CounterpartyID string
+	Currency       string
+	MinAmount      *Amount    // inclusive
+	MaxAmount      *Amount    // inclusive
+	From           *time.Time // processed at or after
+	To             *time.Time // processed before
+}
+
+// ResultQuery is one page of a result search. Results are ordered by SortBy, then by ID
+// in the same direction, so that pages are stable.
+type ResultQuery struct {
+	TenantID   string // only results of this tenant are searched; empty is the default tenant
+	Filter     ResultFilter
+	SortBy     string // one of the ResultSort constants
+	Descending bool
+	Limit      int
+	After      *ResultCursor // start after this result; nil for the first page
+}
+
+// ResultCursor is the sort key of the last result on a page
+type ResultCursor struct {
+	SortBy      string    `json:"sort_by"`
+	Descending  bool      `json:"desc"`
+	ID          string    `json:"id"`
+	ProcessedAt time.Time `json:"processed_at"`
+	RiskScore   float64   `json:"risk_score"`
+	Amount      Amount    `json:"amount"`
+}
+
+// ResultPage is one page of search results. NextCursor is empty on the last page.
+type ResultPage struct {
+	Results    []*ValidationResult `json:"results"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
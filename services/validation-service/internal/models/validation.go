@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -8,13 +9,26 @@ import (
 type ValidationRequest struct {
 	TransactionID string                 `json:"transaction_id" binding:"required"`
 	Type          string                 `json:"type" binding:"required"`
-	Amount        float64                `json:"amount" binding:"required,gt=0"`
+	Amount        Amount                 `json:"amount"`
 	Currency      string                 `json:"currency" binding:"required,len=3"`
 	Counterparty  Counterparty           `json:"counterparty" binding:"required"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	Timestamp     time.Time              `json:"timestamp"`
 }
 
+// Validate checks constraints that struct binding cannot express: the amount must be
+// positive and must not have more decimals than the currency's minor unit allows
+func (r *ValidationRequest) Validate() error {
+	if !r.Amount.IsPositive() {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+	if !r.Amount.FitsCurrency(r.Currency) {
+		units, _ := CurrencyMinorUnits(r.Currency)
+		return fmt.Errorf("amount %s has more than %d decimal places allowed for %s", r.Amount, units, r.Currency)
+	}
+	return nil
+}
+
 // Counterparty represents transaction counterparty information
 type Counterparty struct {
 	ID   string `json:"id" binding:"required"`
@@ -24,15 +38,21 @@ type Counterparty struct {
 
 // ValidationResult represents the result of a transaction validation
 type ValidationResult struct {
-	ID            string                 `json:"id"`
-	TransactionID string                 `json:"transaction_id"`
-	Status        ValidationStatus       `json:"status"`
-	Rules         []RuleResult           `json:"rules"`
-	ErrorCode     string                 `json:"error_code,omitempty"`
-	ErrorMessage  string                 `json:"error_message,omitempty"`
-	ProcessedAt   time.Time              `json:"processed_at"`
-	ProcessingTime time.Duration         `json:"processing_time"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	ID             string                 `json:"id"`
+	TenantID       string                 `json:"tenant_id,omitempty"`
+	TransactionID  string                 `json:"transaction_id"`
+	CounterpartyID string                 `json:"counterparty_id,omitempty"`
+	Amount         Amount                 `json:"amount"`
+	Currency       string                 `json:"currency,omitempty"`
+	Status         ValidationStatus       `json:"status"`
+	Rules          []RuleResult           `json:"rules"`
+	ErrorCode      string                 `json:"error_code,omitempty"`
+	ErrorMessage   string                 `json:"error_message,omitempty"`
+	ProcessedAt    time.Time              `json:"processed_at"`
+	ProcessingTime time.Duration          `json:"processing_time"`
+	RiskScore      float64                `json:"risk_score"` // sum of the weights of failed rules
+	RequestedBy    *Caller                `json:"requested_by,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // ValidationStatus represents the validation status
@@ -42,27 +62,90 @@ const (
 	ValidationStatusPending ValidationStatus = "PENDING"
 	ValidationStatusPassed  ValidationStatus = "PASSED"
 	ValidationStatusFailed  ValidationStatus = "FAILED"
+	ValidationStatusReview  ValidationStatus = "REVIEW"
 	ValidationStatusError   ValidationStatus = "ERROR"
 )
 
 // RuleResult represents the result of a single validation rule
 type RuleResult struct {
-	RuleID      string    `json:"rule_id"`
-	RuleName    string    `json:"rule_name"`
-	Status      string    `json:"status"` // PASSED, FAILED, SKIPPED
-	Message     string    `json:"message,omitempty"`
-	ProcessedAt time.Time `json:"processed_at"`
+	RuleID      string                 `json:"rule_id"`
+	RuleName    string                 `json:"rule_name"`
+	Status      string                 `json:"status"` // PASSED, FAILED, FLAGGED, SKIPPED, TIMEOUT, ERROR
+	Message     string                 `json:"message,omitempty"`
+	ProcessedAt time.Time              `json:"processed_at"`
+	Weight      float64                `json:"weight,omitempty"` // risk weight added to the score
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Rule severities. A rule's severity sets its default risk weight.
+const (
+	RuleSeverityLow      = "LOW"
+	RuleSeverityMedium   = "MEDIUM"
+	RuleSeverityHigh     = "HIGH"
+	RuleSeverityCritical = "CRITICAL"
+)
+
+// Rule error policies decide what a rule that ends in ERROR or TIMEOUT does to the outcome
+const (
+	RuleOnErrorFailClosed = "FAIL_CLOSED" // add the rule's weight to the risk score, as if it failed
+	RuleOnErrorFailOpen   = "FAIL_OPEN"   // ignore the rule
+	RuleOnErrorError      = "ERROR"       // make the overall status ERROR unless it is FAILED
+)
+
 // ValidationRule represents a validation rule configuration
 type ValidationRule struct {
 	ID          string                 `json:"id"`
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
-	Type        string                 `json:"type"` // AMOUNT, CURRENCY, COUNTERPARTY, etc.
+	Type        string                 `json:"type"` // AMOUNT_LIMIT, CURRENCY_CHECK, COUNTERPARTY_CHECK, etc.
 	Enabled     bool                   `json:"enabled"`
 	Priority    int                    `json:"priority"`
+	Severity    string                 `json:"severity,omitempty"` // LOW, MEDIUM, HIGH, CRITICAL
+	Weight      float64                `json:"weight,omitempty"`   // overrides the severity's weight
+	Timeout     string                 `json:"timeout,omitempty"`  // e.g. "250ms"; overrides the default rule timeout
+	OnError     string                 `json:"on_error,omitempty"` // FAIL_CLOSED (default), FAIL_OPEN or ERROR
+	Blocking    bool                   `json:"blocking,omitempty"` // skip lower priority rules when this rule fails
 	Config      map[string]interface{} `json:"config"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// RuleRequest represents a request to create or replace a validation rule.
+// It is also the on-disk format of rule files.
+type RuleRequest struct {
+	ID          string                 `json:"id" yaml:"id"`
+	Name        string                 `json:"name" yaml:"name" binding:"required"`
+	Description string                 `json:"description" yaml:"description"`
+	Type        string                 `json:"type" yaml:"type" binding:"required"`
+	Enabled     *bool                  `json:"enabled" yaml:"enabled"`
+	Priority    int                    `json:"priority" yaml:"priority"`
+	Severity    string                 `json:"severity" yaml:"severity"`
+	Weight      float64                `json:"weight" yaml:"weight"`
+	Timeout     string                 `json:"timeout" yaml:"timeout"`
+	OnError     string                 `json:"on_error" yaml:"on_error"`
+	Blocking    bool                   `json:"blocking" yaml:"blocking"`
+	Config      map[string]interface{} `json:"config" yaml:"config"`
+}
+
+// ToRule converts the request into a rule, enabling it unless explicitly disabled
+func (r *RuleRequest) ToRule() ValidationRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return ValidationRule{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Type:        r.Type,
+		Enabled:     enabled,
+		Priority:    r.Priority,
+		Severity:    r.Severity,
+		Weight:      r.Weight,
+		Timeout:     r.Timeout,
+		OnError:     r.OnError,
+		Blocking:    r.Blocking,
+		Config:      r.Config,
+	}
+}
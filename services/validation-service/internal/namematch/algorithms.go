@@ -0,0 +1,176 @@
+package namematch
+
+import "strings"
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings in [0, 1]
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 && len(s2) == 0 {
+		return 1
+	}
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	matchDistance := max(len(s1), len(s2))/2 - 1
+	if matchDistance < 0 {
+		matchDistance = 0
+	}
+
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		start := max(0, i-matchDistance)
+		end := min(len(s2), i+matchDistance+1)
+		for j := start; j < end; j++ {
+			if matched2[j] || s1[i] != s2[j] {
+				continue
+			}
+			matched1[i] = true
+			matched2[j] = true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	k := 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	// Boost for a common prefix of up to four characters
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// levenshteinSimilarity returns 1 minus the edit distance divided by the longer length
+func levenshteinSimilarity(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	longest := max(len(s1), len(s2))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(s1, s2))/float64(longest)
+}
+
+func levenshtein(s1, s2 []rune) int {
+	previous := make([]int, len(s2)+1)
+	current := make([]int, len(s2)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		current[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(s2)]
+}
+
+// phoneticSimilarity compares the Soundex codes of the (sorted) tokens of two names.
+// The score is the share of token positions whose codes agree, over the longer name.
+func phoneticSimilarity(a, b []string) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+
+	codesA := soundexCodes(a)
+	codesB := soundexCodes(b)
+
+	// Tokens are compared as multisets so differing token order does not matter
+	counts := make(map[string]int, len(codesA))
+	for _, code := range codesA {
+		counts[code]++
+	}
+	agree := 0
+	for _, code := range codesB {
+		if counts[code] > 0 {
+			counts[code]--
+			agree++
+		}
+	}
+
+	return float64(agree) / float64(longest)
+}
+
+func soundexCodes(tokens []string) []string {
+	codes := make([]string, len(tokens))
+	for i, token := range tokens {
+		codes[i] = soundex(token)
+	}
+	return codes
+}
+
+// soundexDigits maps letters to American Soundex digits; vowels and H, W, Y map to 0
+var soundexDigits = map[rune]byte{
+	'B': '1', 'F': '1', 'P': '1', 'V': '1',
+	'C': '2', 'G': '2', 'J': '2', 'K': '2', 'Q': '2', 'S': '2', 'X': '2', 'Z': '2',
+	'D': '3', 'T': '3',
+	'L': '4',
+	'M': '5', 'N': '5',
+	'R': '6',
+}
+
+// soundex returns the four character American Soundex code of an upper-case token
+func soundex(token string) string {
+	runes := []rune(token)
+	if len(runes) == 0 {
+		return ""
+	}
+
+	var code strings.Builder
+	code.WriteRune(runes[0])
+	last := soundexDigits[runes[0]]
+
+	for _, r := range runes[1:] {
+		if code.Len() == 4 {
+			break
+		}
+		digit, ok := soundexDigits[r]
+		switch {
+		case !ok && (r == 'H' || r == 'W'):
+			// H and W do not separate letters with the same code
+			continue
+		case !ok:
+			last = 0
+		case digit != last:
+			code.WriteByte(digit)
+			last = digit
+		}
+	}
+
+	for code.Len() < 4 {
+		code.WriteByte('0')
+	}
+	return code.String()
+}
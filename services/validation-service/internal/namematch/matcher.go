@@ -0,0 +1,78 @@
+package namematch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported scoring algorithms
+const (
+	AlgorithmExact       = "exact"
+	AlgorithmJaroWinkler = "jaro_winkler"
+	AlgorithmLevenshtein = "levenshtein"
+	AlgorithmPhonetic    = "phonetic"
+)
+
+// DefaultThreshold is used when a matcher is configured without a threshold
+const DefaultThreshold = 0.9
+
+// Matcher scores the similarity of two names with one algorithm and decides
+// whether the score is high enough to count as a match
+type Matcher struct {
+	algorithm string
+	threshold float64
+}
+
+// NewMatcher creates a matcher. The threshold must be in (0, 1]; scores at or above
+// it are matches.
+func NewMatcher(algorithm string, threshold float64) (*Matcher, error) {
+	switch algorithm {
+	case AlgorithmExact, AlgorithmJaroWinkler, AlgorithmLevenshtein, AlgorithmPhonetic:
+	default:
+		return nil, fmt.Errorf("unsupported name matching algorithm %q", algorithm)
+	}
+
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("name matching threshold must be between 0 and 1, got %v", threshold)
+	}
+
+	return &Matcher{algorithm: algorithm, threshold: threshold}, nil
+}
+
+// Algorithm returns the scoring algorithm name
+func (m *Matcher) Algorithm() string {
+	return m.algorithm
+}
+
+// Threshold returns the minimum score for a match
+func (m *Matcher) Threshold() float64 {
+	return m.threshold
+}
+
+// Score returns the similarity of two raw names in [0, 1]
+func (m *Matcher) Score(a, b string) float64 {
+	return m.ScoreTokens(Tokens(a), Tokens(b))
+}
+
+// ScoreTokens returns the similarity of two names already split by Tokens
+func (m *Matcher) ScoreTokens(a, b []string) float64 {
+	switch m.algorithm {
+	case AlgorithmJaroWinkler:
+		return jaroWinkler(strings.Join(a, " "), strings.Join(b, " "))
+	case AlgorithmLevenshtein:
+		return levenshteinSimilarity(strings.Join(a, " "), strings.Join(b, " "))
+	case AlgorithmPhonetic:
+		return phoneticSimilarity(a, b)
+	default:
+		if strings.Join(a, " ") == strings.Join(b, " ") {
+			return 1
+		}
+		return 0
+	}
+}
+
+// Match scores two names and reports whether the score reaches the threshold
+func (m *Matcher) Match(a, b string) (float64, bool) {
+	score := m.Score(a, b)
+	return score, score >= m.threshold
+}
@@ -0,0 +1,80 @@
+package namematch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ALI MUHAMMAD", Normalize("Muhammad Ali"))
+	assert.Equal(t, "ALI MUHAMMAD", Normalize("ALI, Muhammad"))
+	assert.Equal(t, "FRANCOIS MULLER", Normalize("  François   Müller "))
+	assert.Equal(t, "AERO CARIBBEAN", Normalize("Aero-Caribbean."))
+	assert.Equal(t, "", Normalize(" -- "))
+}
+
+func TestSoundex(t *testing.T) {
+	assert.Equal(t, "R163", soundex("ROBERT"))
+	assert.Equal(t, "R163", soundex("RUPERT"))
+	assert.Equal(t, "A261", soundex("ASHCRAFT"))
+	assert.Equal(t, "T522", soundex("TYMCZAK"))
+	assert.Equal(t, "P236", soundex("PFISTER"))
+}
+
+func TestMatcher_Algorithms(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		a, b      string
+		match     bool
+	}{
+		{AlgorithmExact, "Muhammad Ali", "Ali, MUHAMMAD", true},
+		{AlgorithmExact, "Mohammad Ali", "Muhammad Ali", false},
+		{AlgorithmJaroWinkler, "Mohammad Ali", "Muhammad Ali", true},
+		{AlgorithmJaroWinkler, "Mohammad Ali", "Acme Trading", false},
+		{AlgorithmLevenshtein, "Mohammad Ali", "Muhammad Ali", true},
+		{AlgorithmLevenshtein, "Mohammad Ali", "Muhammad Alibaba", false},
+		{AlgorithmPhonetic, "Mohammad Ali", "Ali Muhammad", true},
+		{AlgorithmPhonetic, "Mohammad Ali", "Robert Ali", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm+" "+tt.a+" vs "+tt.b, func(t *testing.T) {
+			threshold := 0.85
+			if tt.algorithm == AlgorithmExact || tt.algorithm == AlgorithmPhonetic {
+				threshold = 1
+			}
+			matcher, err := NewMatcher(tt.algorithm, threshold)
+			require.NoError(t, err)
+
+			score, match := matcher.Match(tt.a, tt.b)
+			assert.Equal(t, tt.match, match, "score %.3f", score)
+			assert.GreaterOrEqual(t, score, 0.0)
+			assert.LessOrEqual(t, score, 1.0)
+		})
+	}
+}
+
+func TestJaroWinkler_KnownValues(t *testing.T) {
+	assert.InDelta(t, 0.961, jaroWinkler("MARTHA", "MARHTA"), 0.001)
+	assert.InDelta(t, 0.840, jaroWinkler("DWAYNE", "DUANE"), 0.001)
+	assert.Equal(t, 1.0, jaroWinkler("SAME", "SAME"))
+	assert.Equal(t, 0.0, jaroWinkler("ABC", ""))
+}
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	assert.InDelta(t, 1-3.0/7.0, levenshteinSimilarity("KITTEN", "SITTING"), 0.0001)
+	assert.Equal(t, 1.0, levenshteinSimilarity("", ""))
+}
+
+func TestNewMatcher_Invalid(t *testing.T) {
+	_, err := NewMatcher("soundalike", 0.9)
+	assert.Error(t, err)
+
+	_, err = NewMatcher(AlgorithmJaroWinkler, 0)
+	assert.Error(t, err)
+
+	_, err = NewMatcher(AlgorithmJaroWinkler, 1.5)
+	assert.Error(t, err)
+}
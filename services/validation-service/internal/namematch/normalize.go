@@ -0,0 +1,37 @@
+package namematch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize prepares a name for comparison: diacritics are removed, letters are
+// upper-cased, punctuation becomes a token separator and tokens are sorted so that
+// "Muhammad Ali", "ALI, Muhammad" and "Ali Mühammad" normalise to the same order.
+func Normalize(name string) string {
+	return strings.Join(Tokens(name), " ")
+}
+
+// Tokens returns the sorted, normalised tokens of a name
+func Tokens(name string) []string {
+	var b strings.Builder
+	b.Grow(len(name))
+
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining mark left over from decomposing an accented letter
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteByte(' ')
+		}
+	}
+
+	tokens := strings.Fields(b.String())
+	sort.Strings(tokens)
+	return tokens
+}
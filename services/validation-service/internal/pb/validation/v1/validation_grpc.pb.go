@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: validation/v1/validation.proto
+
+package validationv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ValidationService_Validate_FullMethodName       = "/validation.v1.ValidationService/Validate"
+	ValidationService_GetResult_FullMethodName      = "/validation.v1.ValidationService/GetResult"
+	ValidationService_ValidateStream_FullMethodName = "/validation.v1.ValidationService/ValidateStream"
+)
+
+// ValidationServiceClient is the client API for ValidationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ValidationServiceClient interface {
+	// Validate validates a single transaction (POST /api/validate)
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// GetResult returns a stored validation result (GET /api/validate/{id})
+	GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*GetResultResponse, error)
+	// ValidateStream validates each request as it arrives and streams back one
+	// response per request, in order. An invalid request yields an error response
+	// for that request only; the stream stays open.
+	ValidateStream(ctx context.Context, opts ...grpc.CallOption) (ValidationService_ValidateStreamClient, error)
+}
+
+type validationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidationServiceClient(cc grpc.ClientConnInterface) ValidationServiceClient {
+	return &validationServiceClient{cc}
+}
+
+func (c *validationServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, ValidationService_Validate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*GetResultResponse, error) {
+	out := new(GetResultResponse)
+	err := c.cc.Invoke(ctx, ValidationService_GetResult_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) ValidateStream(ctx context.Context, opts ...grpc.CallOption) (ValidationService_ValidateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ValidationService_ServiceDesc.Streams[0], ValidationService_ValidateStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &validationServiceValidateStreamClient{stream}
+	return x, nil
+}
+
+type ValidationService_ValidateStreamClient interface {
+	Send(*ValidateStreamRequest) error
+	Recv() (*ValidateStreamResponse, error)
+	grpc.ClientStream
+}
+
+type validationServiceValidateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *validationServiceValidateStreamClient) Send(m *ValidateStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *validationServiceValidateStreamClient) Recv() (*ValidateStreamResponse, error) {
+	m := new(ValidateStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValidationServiceServer is the server API for ValidationService service.
+// All implementations must embed UnimplementedValidationServiceServer
+// for forward compatibility
+type ValidationServiceServer interface {
+	// Validate validates a single transaction (POST /api/validate)
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// GetResult returns a stored validation result (GET /api/validate/{id})
+	GetResult(context.Context, *GetResultRequest) (*GetResultResponse, error)
+	// ValidateStream validates each request as it arrives and streams back one
+	// response per request, in order. An invalid request yields an error response
+	// for that request only; the stream stays open.
+	ValidateStream(ValidationService_ValidateStreamServer) error
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+// UnimplementedValidationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedValidationServiceServer struct {
+}
+
+func (UnimplementedValidationServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedValidationServiceServer) GetResult(context.Context, *GetResultRequest) (*GetResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResult not implemented")
+}
+func (UnimplementedValidationServiceServer) ValidateStream(ValidationService_ValidateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ValidateStream not implemented")
+}
+func (UnimplementedValidationServiceServer) mustEmbedUnimplementedValidationServiceServer() {}
+
+// UnsafeValidationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidationServiceServer will
+// result in compilation errors.
+type UnsafeValidationServiceServer interface {
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+func RegisterValidationServiceServer(s grpc.ServiceRegistrar, srv ValidationServiceServer) {
+	s.RegisterService(&ValidationService_ServiceDesc, srv)
+}
+
+func _ValidationService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidationService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_GetResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).GetResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidationService_GetResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).GetResult(ctx, req.(*GetResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_ValidateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ValidationServiceServer).ValidateStream(&validationServiceValidateStreamServer{stream})
+}
+
+type ValidationService_ValidateStreamServer interface {
+	Send(*ValidateStreamResponse) error
+	Recv() (*ValidateStreamRequest, error)
+	grpc.ServerStream
+}
+
+type validationServiceValidateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *validationServiceValidateStreamServer) Send(m *ValidateStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *validationServiceValidateStreamServer) Recv() (*ValidateStreamRequest, error) {
+	m := new(ValidateStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValidationService_ServiceDesc is the grpc.ServiceDesc for ValidationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ValidationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "validation.v1.ValidationService",
+	HandlerType: (*ValidationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Validate",
+			Handler:    _ValidationService_Validate_Handler,
+		},
+		{
+			MethodName: "GetResult",
+			Handler:    _ValidationService_GetResult_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ValidateStream",
+			Handler:       _ValidationService_ValidateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "validation/v1/validation.proto",
+}
@@ -0,0 +1,271 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets of idle clients are purged
+const sweepInterval = time.Minute
+
+// Quota is a token bucket: Burst requests at once, refilled at Rate requests per second.
+// A zero Rate means unlimited.
+type Quota struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether the quota lets every request through
+func (q Quota) Unlimited() bool {
+	return q.Rate <= 0
+}
+
+// ParseQuotas parses a comma separated list of "client:rate:burst" entries, where the
+// client is a caller subject or an IP address
+func ParseQuotas(spec string) (map[string]Quota, error) {
+	quotas := make(map[string]Quota)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// IPv6 addresses contain colons, so the numbers are taken from the right
+		rest, burst, ok := cutLast(entry, ":")
+		client, rate, ok2 := cutLast(rest, ":")
+		if !ok || !ok2 || client == "" {
+			return nil, fmt.Errorf("rate limit entry %q must be client:rate:burst", entry)
+		}
+
+		quota, err := NewQuota(rate, burst)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit for %s: %w", client, err)
+		}
+		quotas[client] = quota
+	}
+	return quotas, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// NewQuota parses a rate and a burst, which defaults to DefaultBurst when empty
+func NewQuota(rate, burst string) (Quota, error) {
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil || r < 0 || math.IsInf(r, 0) {
+		return Quota{}, fmt.Errorf("rate %q must be a non-negative number", rate)
+	}
+	quota := Quota{Rate: r, Burst: DefaultBurst(r)}
+	if burst != "" {
+		b, err := strconv.Atoi(burst)
+		if err != nil || b < 1 {
+			return Quota{}, fmt.Errorf("burst %q must be a positive integer", burst)
+		}
+		quota.Burst = b
+	}
+	return quota, nil
+}
+
+// DefaultBurst is the burst used when none is configured: one second's worth of
+// requests, and at least one
+func DefaultBurst(rate float64) int {
+	return int(math.Max(1, math.Ceil(rate)))
+}
+
+// Decision is the outcome of a request against its client's quota
+type Decision struct {
+	Allowed    bool
+	Limit      int           // the bucket size; 0 when unlimited
+	Remaining  int           // whole requests left in the bucket
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request would be allowed; 0 when allowed
+}
+
+// bucket is one client's token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	quota   Quota
+}
+
+// Limiter keeps a token bucket per client. Buckets are per instance, so with several
+// replicas each enforces the quota separately.
+type Limiter struct {
+	defaultQuota Quota
+	quotas       map[string]Quota
+	now          func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New creates a limiter applying quotas to the clients they name and defaultQuota to
+// every other client
+func New(defaultQuota Quota, quotas map[string]Quota) *Limiter {
+	return &Limiter{
+		defaultQuota: defaultQuota,
+		quotas:       quotas,
+		now:          time.Now,
+		buckets:      make(map[string]*bucket),
+		lastSweep:    time.Now(),
+	}
+}
+
+// Enabled reports whether any client is limited
+func (l *Limiter) Enabled() bool {
+	if !l.defaultQuota.Unlimited() {
+		return true
+	}
+	for _, quota := range l.quotas {
+		if !quota.Unlimited() {
+			return true
+		}
+	}
+	return false
+}
+
+// Quota returns the quota that applies to the client
+func (l *Limiter) Quota(client string) Quota {
+	if quota, ok := l.quotas[client]; ok {
+		return quota
+	}
+	return l.defaultQuota
+}
+
+// Allow takes a token from the client's bucket if one is available
+func (l *Limiter) Allow(client string) Decision {
+	return l.AllowN(client, 1)
+}
+
+// AllowN takes n tokens from the client's bucket if that many are available, for a
+// request doing the work of n, such as a batch. More tokens than the burst are never
+// available.
+func (l *Limiter) AllowN(client string, n int) Decision {
+	quota := l.Quota(client)
+	if quota.Unlimited() {
+		return Decision{Allowed: true}
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(client, quota, now)
+	decision := Decision{Limit: quota.Burst}
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((float64(n) - b.tokens) / quota.Rate)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = seconds((float64(quota.Burst) - b.tokens) / quota.Rate)
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	return decision
+}
+
+// bucket returns the client's bucket refilled up to now, creating a full one for new
+// clients and clients whose quota changed. Callers must hold l.mu.
+func (l *Limiter) bucket(client string, quota Quota, now time.Time) *bucket {
+	b, ok := l.buckets[client]
+	if !ok || b.quota != quota {
+		b = &bucket{tokens: float64(quota.Burst), updated: now, quota: quota}
+		l.buckets[client] = b
+	}
+	b.refill(now)
+	return b
+}
+
+// refill adds the tokens earned since the last update, up to the burst
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(float64(b.quota.Burst), b.tokens+elapsed.Seconds()*b.quota.Rate)
+		b.updated = now
+	}
+}
+
+// sweep drops buckets that have refilled completely, as they are equivalent to new ones.
+// Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.quota.Burst) {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// chargeKey is the context key of the bucket a request was allowed against
+type chargeKey struct{}
+
+// charge is the bucket further work of a request is taken from
+type charge struct {
+	limiter *Limiter
+	client  string
+}
+
+// NewContext returns a copy of ctx recording that its request was allowed against the
+// client's bucket of limiter, so that Charge can take further tokens from it
+func NewContext(ctx context.Context, limiter *Limiter, client string) context.Context {
+	return context.WithValue(ctx, chargeKey{}, charge{limiter: limiter, client: client})
+}
+
+// Charge takes n more tokens, if available, from the bucket the request in ctx was
+// allowed against. Requests that were not rate limited are always allowed.
+func Charge(ctx context.Context, n int) Decision {
+	c, ok := ctx.Value(chargeKey{}).(charge)
+	if !ok {
+		return Decision{Allowed: true}
+	}
+	return c.limiter.AllowN(c.client, n)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// InFlight caps the number of requests processed at once across all clients
+type InFlight struct {
+	slots chan struct{}
+}
+
+// NewInFlight creates a cap of max concurrent requests
+func NewInFlight(max int) *InFlight {
+	return &InFlight{slots: make(chan struct{}, max)}
+}
+
+// Limit returns the maximum number of concurrent requests
+func (f *InFlight) Limit() int {
+	return cap(f.slots)
+}
+
+// Acquire takes a slot without waiting, reporting false when all are taken. Each
+// successful Acquire must be followed by Release.
+func (f *InFlight) Acquire() bool {
+	select {
+	case f.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release returns a slot taken by Acquire
+func (f *InFlight) Release() {
+	<-f.slots
+}
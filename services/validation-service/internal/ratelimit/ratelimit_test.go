@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestLimiter(defaultQuota Quota, quotas map[string]Quota) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)}
+	limiter := New(defaultQuota, quotas)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestParseQuotas(t *testing.T) {
+	quotas, err := ParseQuotas("batch-client:5:10, 10.0.0.7:0.5:1, ::1:2:3, exempt:0:1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Quota{
+		"batch-client": {Rate: 5, Burst: 10},
+		"10.0.0.7":     {Rate: 0.5, Burst: 1},
+		"::1":          {Rate: 2, Burst: 3},
+		"exempt":       {Rate: 0, Burst: 1},
+	}, quotas)
+
+	quotas, err = ParseQuotas("")
+	require.NoError(t, err)
+	assert.Empty(t, quotas)
+
+	for _, spec := range []string{"batch", "batch:5", ":5:10", "batch:fast:10", "batch:-1:10", "batch:5:0", "batch:5:x"} {
+		_, err := ParseQuotas(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	limiter, clock := newTestLimiter(Quota{Rate: 2, Burst: 3}, nil)
+	assert.True(t, limiter.Enabled())
+
+	for i := 2; i >= 0; i-- {
+		decision := limiter.Allow("client-a")
+		require.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, i, decision.Remaining)
+	}
+
+	decision := limiter.Allow("client-a")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, decision.Reset)
+
+	// Other clients have their own bucket
+	assert.True(t, limiter.Allow("client-b").Allowed)
+
+	// Tokens refill at the rate, up to the burst
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("client-a").Allowed)
+	assert.False(t, limiter.Allow("client-a").Allowed)
+
+	clock.now = clock.now.Add(time.Hour)
+	decision = limiter.Allow("client-a")
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Remaining)
+}
+
+func TestLimiter_ClientQuotas(t *testing.T) {
+	limiter, _ := newTestLimiter(Quota{}, map[string]Quota{
+		"batch-client": {Rate: 1, Burst: 1},
+	})
+	assert.True(t, limiter.Enabled())
+
+	assert.True(t, limiter.Allow("batch-client").Allowed)
+	assert.False(t, limiter.Allow("batch-client").Allowed)
+
+	// Clients without a quota fall back to the default, here unlimited
+	for i := 0; i < 100; i++ {
+		decision := limiter.Allow("transaction-service")
+		require.True(t, decision.Allowed)
+		assert.Zero(t, decision.Limit)
+	}
+
+	disabled, _ := newTestLimiter(Quota{}, map[string]Quota{"exempt": {Rate: 0, Burst: 1}})
+	assert.False(t, disabled.Enabled())
+}
+
+func TestLimiter_AllowN(t *testing.T) {
+	limiter, clock := newTestLimiter(Quota{Rate: 2, Burst: 5}, nil)
+
+	decision := limiter.AllowN("client-a", 4)
+	require.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+
+	// A request needing more tokens than are left takes none
+	decision = limiter.AllowN("client-a", 3)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+	assert.Equal(t, time.Second, decision.RetryAfter)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, limiter.AllowN("client-a", 3).Allowed)
+
+	// More tokens than the burst are never available
+	clock.now = clock.now.Add(time.Hour)
+	assert.False(t, limiter.AllowN("client-a", 6).Allowed)
+	assert.True(t, limiter.AllowN("client-a", 5).Allowed)
+
+	unlimited, _ := newTestLimiter(Quota{}, nil)
+	assert.True(t, unlimited.AllowN("client-a", 100).Allowed)
+}
+
+func TestCharge(t *testing.T) {
+	limiter, _ := newTestLimiter(Quota{Rate: 1, Burst: 3}, nil)
+
+	// Requests that were not rate limited are always allowed
+	assert.True(t, Charge(context.Background(), 10).Allowed)
+
+	ctx := NewContext(context.Background(), limiter, "client-a")
+	require.True(t, Charge(ctx, 2).Allowed)
+	decision := limiter.Allow("client-a")
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.False(t, Charge(ctx, 1).Allowed)
+	assert.True(t, limiter.Allow("client-b").Allowed, "other clients are not charged")
+}
+
+func TestLimiter_SweepsIdleBuckets(t *testing.T) {
+	limiter, clock := newTestLimiter(Quota{Rate: 1, Burst: 2}, nil)
+	limiter.lastSweep = clock.now
+
+	limiter.Allow("client-a")
+	limiter.Allow("client-b")
+	clock.now = clock.now.Add(sweepInterval)
+	limiter.Allow("client-c")
+
+	assert.Len(t, limiter.buckets, 1, "only the bucket that is not full is kept")
+	assert.Contains(t, limiter.buckets, "client-c")
+}
+
+func TestInFlight(t *testing.T) {
+	inFlight := NewInFlight(2)
+	assert.Equal(t, 2, inFlight.Limit())
+
+	assert.True(t, inFlight.Acquire())
+	assert.True(t, inFlight.Acquire())
+	assert.False(t, inFlight.Acquire())
+
+	inFlight.Release()
+	assert.True(t, inFlight.Acquire())
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// MemoryResultRepository keeps validation results in process memory.
+// It is intended for tests and local development.
+type MemoryResultRepository struct {
+	mu      sync.RWMutex
+	results map[string]map[string]models.ValidationResult // by tenant, then ID
+}
+
+// NewMemoryResultRepository creates an empty in-memory result repository
+func NewMemoryResultRepository() *MemoryResultRepository {
+	return &MemoryResultRepository{
+		results: make(map[string]map[string]models.ValidationResult),
+	}
+}
+
+// Save stores a copy of the validation result in its tenant's partition
+func (r *MemoryResultRepository) Save(ctx context.Context, result *models.ValidationResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	partition, ok := r.results[result.TenantID]
+	if !ok {
+		partition = make(map[string]models.ValidationResult)
+		r.results[result.TenantID] = partition
+	}
+	partition[result.ID] = copyResult(result)
+	return nil
+}
+
+// FindByID returns a copy of the tenant's stored validation result
+func (r *MemoryResultRepository) FindByID(ctx context.Context, tenantID, id string) (*models.ValidationResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result, ok := r.results[tenantID][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	found := copyResult(&result)
+	return &found, nil
+}
+
+// Search filters and sorts the stored results of the query's tenant
+func (r *MemoryResultRepository) Search(ctx context.Context, query models.ResultQuery) ([]*models.ValidationResult, error) {
+	r.mu.RLock()
+	matches := make([]*models.ValidationResult, 0)
+	for _, result := range r.results[query.TenantID] {
+		if matchesFilter(&result, query.Filter) {
+			found := copyResult(&result)
+			matches = append(matches, &found)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareResults(matches[i], matches[j], query) < 0
+	})
+
+	start := 0
+	if query.After != nil {
+		start = sort.Search(len(matches), func(i int) bool {
+			return compareToCursor(matches[i], query) > 0
+		})
+	}
+
+	end := len(matches)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+	return matches[start:end], nil
+}
+
+// copyResult copies a result deeply enough that changing the copy, including its rules
+// and metadata, never changes the original
+func copyResult(result *models.ValidationResult) models.ValidationResult {
+	copied := *result
+	if result.Rules != nil {
+		copied.Rules = make([]models.RuleResult, len(result.Rules))
+		for i, rule := range result.Rules {
+			rule.Metadata = copyMetadata(rule.Metadata)
+			copied.Rules[i] = rule
+		}
+	}
+	if result.RequestedBy != nil {
+		caller := *result.RequestedBy
+		copied.RequestedBy = &caller
+	}
+	copied.Metadata = copyMetadata(result.Metadata)
+	return copied
+}
+
+// copyMetadata copies metadata along with the maps and slices nested in it
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = copyValue(value)
+	}
+	return copied
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyMetadata(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	case []string:
+		return append([]string(nil), v...)
+	default:
+		return value
+	}
+}
+
+func matchesFilter(result *models.ValidationResult, filter models.ResultFilter) bool {
+	switch {
+	case filter.TransactionID != "" && result.TransactionID != filter.TransactionID:
+		return false
+	case filter.Status != "" && result.Status != filter.Status:
+		return false
+	case filter.CounterpartyID != "" && result.CounterpartyID != filter.CounterpartyID:
+		return false
+	case filter.Currency != "" && result.Currency != filter.Currency:
+		return false
+	case filter.MinAmount != nil && result.Amount.Cmp(*filter.MinAmount) < 0:
+		return false
+	case filter.MaxAmount != nil && result.Amount.Cmp(*filter.MaxAmount) > 0:
+		return false
+	case filter.From != nil && result.ProcessedAt.Before(*filter.From):
+		return false
+	case filter.To != nil && !result.ProcessedAt.Before(*filter.To):
+		return false
+	}
+
+	if filter.FailedRuleID != "" {
+		for _, rule := range result.Rules {
+			if rule.RuleID == filter.FailedRuleID && rule.Status == "FAILED" {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// compareResults orders two results by the query's sort field, then by ID
+func compareResults(a, b *models.ValidationResult, query models.ResultQuery) int {
+	return orderBy(query, compareSortKey(query.SortBy, a, b.ProcessedAt, b.RiskScore, b.Amount), strings.Compare(a.ID, b.ID))
+}
+
+// compareToCursor orders a result relative to the query's cursor
+func compareToCursor(result *models.ValidationResult, query models.ResultQuery) int {
+	after := query.After
+	return orderBy(query, compareSortKey(query.SortBy, result, after.ProcessedAt, after.RiskScore, after.Amount), strings.Compare(result.ID, after.ID))
+}
+
+func compareSortKey(sortBy string, result *models.ValidationResult, processedAt time.Time, riskScore float64, amount models.Amount) int {
+	switch sortBy {
+	case models.ResultSortRiskScore:
+		switch {
+		case result.RiskScore < riskScore:
+			return -1
+		case result.RiskScore > riskScore:
+			return 1
+		}
+		return 0
+	case models.ResultSortAmount:
+		return result.Amount.Cmp(amount)
+	default:
+		return result.ProcessedAt.Compare(processedAt)
+	}
+}
+
+func orderBy(query models.ResultQuery, key, id int) int {
+	c := key
+	if c == 0 {
+		c = id
+	}
+	if query.Descending {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// pendingReservationTimeout is how long a key stays reserved without a result before
+// another request may take it over, e.g. after the first request's process crashed
+const pendingReservationTimeout = time.Minute
+
+// idempotencySweepInterval is how often expired keys are purged from memory
+const idempotencySweepInterval = time.Minute
+
+// MemoryIdempotencyRepository keeps idempotency keys in process memory
+type MemoryIdempotencyRepository struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	records   map[string]models.IdempotencyRecord
+	lastSweep time.Time
+}
+
+// NewMemoryIdempotencyRepository creates an empty repository whose keys expire after ttl
+func NewMemoryIdempotencyRepository(ttl time.Duration) *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{
+		ttl:       ttl,
+		records:   make(map[string]models.IdempotencyRecord),
+		lastSweep: time.Now(),
+	}
+}
+
+// Reserve claims the key unless a live record holds it
+func (r *MemoryIdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) >= idempotencySweepInterval {
+		for key, existing := range r.records {
+			if r.expired(existing, now) {
+				delete(r.records, key)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	if existing, ok := r.records[record.Key]; ok && !r.expired(existing, now) {
+		return &existing, false, nil
+	}
+
+	r.records[record.Key] = *record
+	return nil, true, nil
+}
+
+// Complete records the validation ID for the key
+func (r *MemoryIdempotencyRepository) Complete(ctx context.Context, key, validationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok {
+		return ErrNotFound
+	}
+	record.ValidationID = validationID
+	r.records[key] = record
+	return nil
+}
+
+// Release removes a reservation that has no result
+func (r *MemoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record, ok := r.records[key]; ok && record.ValidationID == "" {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) expired(record models.IdempotencyRecord, now time.Time) bool {
+	if record.ValidationID == "" {
+		return now.Sub(record.CreatedAt) >= pendingReservationTimeout
+	}
+	return now.Sub(record.CreatedAt) >= r.ttl
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryIdempotencyRepository_ReserveCompleteRelease(t *testing.T) {
+	repo := NewMemoryIdempotencyRepository(time.Hour)
+	ctx := context.Background()
+
+	record := &models.IdempotencyRecord{Key: "txn:1", Fingerprint: "abc", CreatedAt: time.Now()}
+	existing, reserved, err := repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, existing)
+
+	// A second reservation sees the in-progress record
+	existing, reserved, err = repo.Reserve(ctx, &models.IdempotencyRecord{Key: "txn:1", Fingerprint: "def", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	require.NotNil(t, existing)
+	assert.Equal(t, "abc", existing.Fingerprint)
+	assert.Empty(t, existing.ValidationID)
+
+	require.NoError(t, repo.Complete(ctx, "txn:1", "val-1"))
+	existing, reserved, err = repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "val-1", existing.ValidationID)
+
+	// Completed keys are kept on release
+	require.NoError(t, repo.Release(ctx, "txn:1"))
+	_, reserved, err = repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+
+	assert.ErrorIs(t, repo.Complete(ctx, "txn:missing", "val-2"), ErrNotFound)
+}
+
+func TestMemoryIdempotencyRepository_Expiry(t *testing.T) {
+	repo := NewMemoryIdempotencyRepository(time.Hour)
+	ctx := context.Background()
+
+	// Completed key past its TTL
+	_, reserved, err := repo.Reserve(ctx, &models.IdempotencyRecord{Key: "txn:old", Fingerprint: "abc", CreatedAt: time.Now().Add(-2 * time.Hour)})
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, repo.Complete(ctx, "txn:old", "val-1"))
+
+	_, reserved, err = repo.Reserve(ctx, &models.IdempotencyRecord{Key: "txn:old", Fingerprint: "def", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	// Abandoned reservation past the pending timeout
+	_, reserved, err = repo.Reserve(ctx, &models.IdempotencyRecord{Key: "txn:stuck", Fingerprint: "abc", CreatedAt: time.Now().Add(-2 * pendingReservationTimeout)})
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, reserved, err = repo.Reserve(ctx, &models.IdempotencyRecord{Key: "txn:stuck", Fingerprint: "abc", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// MemoryJobRepository keeps jobs in process memory; jobs are lost on restart.
+// It is intended for tests and local development.
+type MemoryJobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]models.Job
+}
+
+// NewMemoryJobRepository creates an empty in-memory job repository
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{
+		jobs: make(map[string]models.Job),
+	}
+}
+
+// Save stores a copy of the job, keeping the results already stored for it
+func (r *MemoryJobRepository) Save(ctx context.Context, job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyJob(job)
+	stored.Results = r.jobs[job.ID].Results
+	r.jobs[job.ID] = stored
+	return nil
+}
+
+// AppendResults adds copies of the results to the stored job
+func (r *MemoryJobRepository) AppendResults(ctx context.Context, id string, results []models.BatchItemResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	for _, result := range results {
+		if result.Index >= 0 && result.Index < len(job.Results) {
+			job.Results[result.Index] = result
+		} else {
+			job.Results = append(job.Results, result)
+		}
+	}
+	r.jobs[id] = job
+	return nil
+}
+
+// FindByID returns a copy of the stored job without its items and results
+func (r *MemoryJobRepository) FindByID(ctx context.Context, id string) (*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	job.Items = nil
+	job.Results = nil
+	return &job, nil
+}
+
+// Load returns a copy of the stored job
+func (r *MemoryJobRepository) Load(ctx context.Context, id string) (*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	found := copyJob(&job)
+	return &found, nil
+}
+
+// FindResults returns a copy of the stored job's results
+func (r *MemoryJobRepository) FindResults(ctx context.Context, id string) ([]models.BatchItemResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]models.BatchItemResult(nil), job.Results...), nil
+}
+
+// ListPending returns copies of unfinished jobs and jobs with a pending callback
+func (r *MemoryJobRepository) ListPending(ctx context.Context) ([]*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pending := make([]*models.Job, 0)
+	for _, job := range r.jobs {
+		if job.Status != models.JobStatusCompleted || job.CallbackStatus == models.CallbackStatusPending {
+			found := copyJob(&job)
+			pending = append(pending, &found)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+	return pending, nil
+}
+
+// copyJob copies a job so that the caller and the store do not share slices
+func copyJob(job *models.Job) models.Job {
+	copied := *job
+	copied.Items = append([]models.JobItem(nil), job.Items...)
+	copied.Results = append([]models.BatchItemResult(nil), job.Results...)
+	return copied
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryJobRepository_SaveAndFind(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	job := &models.Job{
+		ID:     "job-1",
+		Status: models.JobStatusQueued,
+		Total:  1,
+		Items:  []models.JobItem{{Error: "bad json"}},
+	}
+	require.NoError(t, repo.Save(ctx, job))
+
+	// Later appends by the caller must not leak into the stored copy
+	job.Results = append(job.Results, models.BatchItemResult{Index: 0})
+
+	found, err := repo.FindByID(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusQueued, found.Status)
+	assert.Nil(t, found.Items, "FindByID leaves the items unread")
+
+	found, err = repo.Load(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Len(t, found.Items, 1)
+	assert.Empty(t, found.Results)
+
+	_, err = repo.FindByID(ctx, "job-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = repo.Load(ctx, "job-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryJobRepository_AppendResults(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	job := &models.Job{ID: "job-1", Status: models.JobStatusRunning, Total: 3}
+	require.NoError(t, repo.Save(ctx, job))
+
+	require.NoError(t, repo.AppendResults(ctx, "job-1", []models.BatchItemResult{{Index: 0}, {Index: 1, Error: "first run"}}))
+	require.NoError(t, repo.AppendResults(ctx, "job-1", []models.BatchItemResult{{Index: 1}, {Index: 2}}))
+
+	// Saving the job again keeps the stored results
+	job.Processed = 3
+	require.NoError(t, repo.Save(ctx, job))
+
+	found, err := repo.Load(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, found.Processed)
+	require.Len(t, found.Results, 3)
+	for i, result := range found.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Empty(t, result.Error, "a repeated item replaces its earlier result")
+	}
+
+	results, err := repo.FindResults(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, found.Results, results)
+	_, err = repo.FindResults(ctx, "job-missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.ErrorIs(t, repo.AppendResults(ctx, "job-missing", nil), ErrNotFound)
+}
+
+func TestMemoryJobRepository_ListPending(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+	base := time.Now()
+
+	jobs := []*models.Job{
+		{ID: "job-running", Status: models.JobStatusRunning, CreatedAt: base.Add(2 * time.Second)},
+		{ID: "job-done", Status: models.JobStatusCompleted, CreatedAt: base},
+		{ID: "job-callback", Status: models.JobStatusCompleted, CallbackStatus: models.CallbackStatusPending, CreatedAt: base.Add(time.Second)},
+		{ID: "job-delivered", Status: models.JobStatusCompleted, CallbackStatus: models.CallbackStatusDelivered, CreatedAt: base},
+	}
+	for _, job := range jobs {
+		require.NoError(t, repo.Save(ctx, job))
+	}
+
+	pending, err := repo.ListPending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "job-callback", pending[0].ID)
+	assert.Equal(t, "job-running", pending[1].ID)
+}
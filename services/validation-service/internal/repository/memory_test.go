@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryResultRepository_SaveAndFind(t *testing.T) {
+	repo := NewMemoryResultRepository()
+	ctx := context.Background()
+
+	result := &models.ValidationResult{
+		ID:            "val-1",
+		TransactionID: "txn-1",
+		Status:        models.ValidationStatusPassed,
+		Rules: []models.RuleResult{
+			{RuleID: "velocity", Status: "PASSED", Metadata: map[string]interface{}{"count": 1, "window": map[string]interface{}{"size": "1h"}}},
+		},
+		ProcessedAt: time.Now(),
+		Metadata:    map[string]interface{}{"channel": "web", "tags": []interface{}{"new"}},
+	}
+
+	err := repo.Save(ctx, result)
+	assert.NoError(t, err)
+
+	// Changes to the saved result must not reach the stored copy
+	result.Rules[0].Status = "FAILED"
+	result.Metadata["channel"] = "changed"
+
+	found, err := repo.FindByID(ctx, "", "val-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "txn-1", found.TransactionID)
+	assert.Equal(t, models.ValidationStatusPassed, found.Status)
+	assert.Equal(t, "PASSED", found.Rules[0].Status)
+	assert.Equal(t, "web", found.Metadata["channel"])
+
+	// Mutating the returned copy must not change the stored result
+	found.Status = models.ValidationStatusFailed
+	found.Rules[0].Status = "FAILED"
+	found.Rules[0].Metadata["count"] = 2
+	found.Rules[0].Metadata["window"].(map[string]interface{})["size"] = "1d"
+	found.Rules = append(found.Rules, models.RuleResult{RuleID: "extra"})
+	found.Metadata["channel"] = "changed"
+	found.Metadata["tags"].([]interface{})[0] = "changed"
+
+	again, err := repo.FindByID(ctx, "", "val-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusPassed, again.Status)
+	require.Len(t, again.Rules, 1)
+	assert.Equal(t, "PASSED", again.Rules[0].Status)
+	assert.Equal(t, 1, again.Rules[0].Metadata["count"])
+	assert.Equal(t, "1h", again.Rules[0].Metadata["window"].(map[string]interface{})["size"])
+	assert.Equal(t, "web", again.Metadata["channel"])
+	assert.Equal(t, []interface{}{"new"}, again.Metadata["tags"])
+
+	// Search returns copies too
+	matches, err := repo.Search(ctx, models.ResultQuery{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	matches[0].Rules[0].Metadata["count"] = 3
+	matches[0].Metadata["channel"] = "changed"
+
+	again, err = repo.FindByID(ctx, "", "val-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, again.Rules[0].Metadata["count"])
+	assert.Equal(t, "web", again.Metadata["channel"])
+}
+
+func TestMemoryResultRepository_FindByID_NotFound(t *testing.T) {
+	repo := NewMemoryResultRepository()
+
+	found, err := repo.FindByID(context.Background(), "", "missing")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, found)
+}
+
+func TestMemoryResultRepository_Search(t *testing.T) {
+	repo := NewMemoryResultRepository()
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, result := range []models.ValidationResult{
+		{ID: "val-1", TransactionID: "txn-1", CounterpartyID: "cp-1", Amount: models.MustParseAmount("100"), Currency: "USD", Status: models.ValidationStatusPassed, RiskScore: 0},
+		{ID: "val-2", TransactionID: "txn-2", CounterpartyID: "cp-1", Amount: models.MustParseAmount("20000"), Currency: "USD", Status: models.ValidationStatusFailed, RiskScore: 100,
+			Rules: []models.RuleResult{{RuleID: "amount-limit", Status: "FAILED"}}},
+		{ID: "val-3", TransactionID: "txn-3", CounterpartyID: "cp-2", Amount: models.MustParseAmount("500"), Currency: "EUR", Status: models.ValidationStatusReview, RiskScore: 50,
+			Rules: []models.RuleResult{{RuleID: "amount-limit", Status: "PASSED"}}},
+	} {
+		result.ProcessedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Save(ctx, &result))
+	}
+
+	ids := func(results []*models.ValidationResult) []string {
+		out := make([]string, 0, len(results))
+		for _, r := range results {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	min := models.MustParseAmount("200")
+	from := base.Add(time.Minute)
+
+	tests := []struct {
+		name  string
+		query models.ResultQuery
+		want  []string
+	}{
+		{"newest first", models.ResultQuery{Descending: true}, []string{"val-3", "val-2", "val-1"}},
+		{"by counterparty", models.ResultQuery{Filter: models.ResultFilter{CounterpartyID: "cp-1"}}, []string{"val-1", "val-2"}},
+		{"by failed rule", models.ResultQuery{Filter: models.ResultFilter{FailedRuleID: "amount-limit"}}, []string{"val-2"}},
+		{"by status", models.ResultQuery{Filter: models.ResultFilter{Status: models.ValidationStatusReview}}, []string{"val-3"}},
+		{"by amount", models.ResultQuery{Filter: models.ResultFilter{MinAmount: &min, Currency: "USD"}}, []string{"val-2"}},
+		{"by time", models.ResultQuery{Filter: models.ResultFilter{From: &from}}, []string{"val-2", "val-3"}},
+		{"by risk score", models.ResultQuery{SortBy: models.ResultSortRiskScore, Descending: true}, []string{"val-2", "val-3", "val-1"}},
+		{"limited", models.ResultQuery{SortBy: models.ResultSortAmount, Limit: 2}, []string{"val-1", "val-3"}},
+		{"after cursor", models.ResultQuery{SortBy: models.ResultSortAmount,
+			After: &models.ResultCursor{ID: "val-3", Amount: models.MustParseAmount("500")}}, []string{"val-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.Search(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(results))
+		})
+	}
+}
+
+func TestMemoryResultRepository_TenantIsolation(t *testing.T) {
+	repo := NewMemoryResultRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, &models.ValidationResult{ID: "val-1", TenantID: "retail", TransactionID: "txn-1"}))
+	require.NoError(t, repo.Save(ctx, &models.ValidationResult{ID: "val-2", TenantID: "corporate", TransactionID: "txn-1"}))
+
+	found, err := repo.FindByID(ctx, "retail", "val-1")
+	require.NoError(t, err)
+	assert.Equal(t, "retail", found.TenantID)
+
+	_, err = repo.FindByID(ctx, "corporate", "val-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = repo.FindByID(ctx, "", "val-1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	results, err := repo.Search(ctx, models.ResultQuery{TenantID: "corporate", Filter: models.ResultFilter{TransactionID: "txn-1"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "val-2", results[0].ID)
+
+	results, err = repo.Search(ctx, models.ResultQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+
+	// Register the PostgreSQL driver
+	_ "github.com/lib/pq"
+)
+
+// schemaStatements are applied in order on startup and must be idempotent
+var schemaStatements = []string{
+	`CREATE TABLE IF NOT EXISTS validation_results (
+		id                 TEXT PRIMARY KEY,
+		transaction_id     TEXT NOT NULL,
+		status             TEXT NOT NULL,
+		rules              JSONB NOT NULL DEFAULT '[]',
+		error_code         TEXT NOT NULL DEFAULT '',
+		error_message      TEXT NOT NULL DEFAULT '',
+		processed_at       TIMESTAMPTZ NOT NULL,
+		processing_time_ns BIGINT NOT NULL DEFAULT 0,
+		metadata           JSONB
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_results_transaction_id
+		ON validation_results (transaction_id)`,
+	`ALTER TABLE validation_results
+		ADD COLUMN IF NOT EXISTS risk_score DOUBLE PRECISION NOT NULL DEFAULT 0`,
+	`ALTER TABLE validation_results
+		ADD COLUMN IF NOT EXISTS counterparty_id TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS amount NUMERIC NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_results_processed_at
+		ON validation_results (processed_at, id)`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_results_counterparty_id
+		ON validation_results (counterparty_id, processed_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_results_rules
+		ON validation_results USING GIN (rules jsonb_path_ops)`,
+	`ALTER TABLE validation_results
+		ADD COLUMN IF NOT EXISTS requested_by_subject TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS requested_by_method TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE validation_results
+		ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_results_tenant_processed_at
+		ON validation_results (tenant_id, processed_at, id)`,
+}
+
+// resultColumns is the column list read by scanResult
+const resultColumns = `id, transaction_id, counterparty_id, amount, currency, status, rules,
+	error_code, error_message, processed_at, processing_time_ns, risk_score, metadata,
+	requested_by_subject, requested_by_method, tenant_id`
+
+// resultSortColumns maps search sort fields to columns
+var resultSortColumns = map[string]string{
+	models.ResultSortProcessedAt: "processed_at",
+	models.ResultSortRiskScore:   "risk_score",
+	models.ResultSortAmount:      "amount",
+}
+
+// PostgresResultRepository stores validation results in PostgreSQL
+type PostgresResultRepository struct {
+	db *sql.DB
+}
+
+// OpenPostgres opens and verifies a PostgreSQL connection pool
+func OpenPostgres(ctx context.Context, databaseURL string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(30 * time.Minute)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewPostgresResultRepository creates a result repository and ensures its schema exists
+func NewPostgresResultRepository(ctx context.Context, db *sql.DB) (*PostgresResultRepository, error) {
+	for _, stmt := range schemaStatements {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("apply schema: %w", err)
+		}
+	}
+
+	return &PostgresResultRepository{db: db}, nil
+}
+
+// Save inserts or replaces a validation result
+func (r *PostgresResultRepository) Save(ctx context.Context, result *models.ValidationResult) error {
+	rules, err := json.Marshal(result.Rules)
+	if err != nil {
+		return fmt.Errorf("encode rules: %w", err)
+	}
+
+	metadata, err := json.Marshal(result.Metadata)
+	if err != nil {
+		return fmt.Errorf("encode metadata: %w", err)
+	}
+
+	var requestedBy models.Caller
+	if result.RequestedBy != nil {
+		requestedBy = *result.RequestedBy
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO validation_results (
+			id, transaction_id, status, rules, error_code, error_message,
+			processed_at, processing_time_ns, risk_score, metadata,
+			counterparty_id, amount, currency, requested_by_subject, requested_by_method,
+			tenant_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (id) DO UPDATE SET
+			transaction_id     = EXCLUDED.transaction_id,
+			counterparty_id    = EXCLUDED.counterparty_id,
+			amount             = EXCLUDED.amount,
+			currency           = EXCLUDED.currency,
+			status             = EXCLUDED.status,
+			rules              = EXCLUDED.rules,
+			error_code         = EXCLUDED.error_code,
+			error_message      = EXCLUDED.error_message,
+			processed_at       = EXCLUDED.processed_at,
+			processing_time_ns = EXCLUDED.processing_time_ns,
+			risk_score         = EXCLUDED.risk_score,
+			metadata           = EXCLUDED.metadata,
+			requested_by_subject = EXCLUDED.requested_by_subject,
+			requested_by_method  = EXCLUDED.requested_by_method,
+			tenant_id          = EXCLUDED.tenant_id`,
+		result.ID,
+		result.TransactionID,
+		string(result.Status),
+		rules,
+		result.ErrorCode,
+		result.ErrorMessage,
+		result.ProcessedAt,
+		int64(result.ProcessingTime),
+		result.RiskScore,
+		metadata,
+		result.CounterpartyID,
+		result.Amount.String(),
+		result.Currency,
+		requestedBy.Subject,
+		requestedBy.Method,
+		result.TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("save validation result %s: %w", result.ID, err)
+	}
+
+	return nil
+}
+
+// FindByID loads a tenant's validation result by its ID
+func (r *PostgresResultRepository) FindByID(ctx context.Context, tenantID, id string) (*models.ValidationResult, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+resultColumns+`
+		FROM validation_results
+		WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+
+	result, err := scanResult(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load validation result %s: %w", id, err)
+	}
+
+	return result, nil
+}
+
+// Search builds a filtered keyset query; the cursor compares (sort column, id) as a row
+func (r *PostgresResultRepository) Search(ctx context.Context, query models.ResultQuery) ([]*models.ValidationResult, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	where("tenant_id = $%d", query.TenantID)
+
+	filter := query.Filter
+	if filter.TransactionID != "" {
+		where("transaction_id = $%d", filter.TransactionID)
+	}
+	if filter.Status != "" {
+		where("status = $%d", string(filter.Status))
+	}
+	if filter.CounterpartyID != "" {
+		where("counterparty_id = $%d", filter.CounterpartyID)
+	}
+	if filter.Currency != "" {
+		where("currency = $%d", filter.Currency)
+	}
+	if filter.MinAmount != nil {
+		where("amount >= $%d", filter.MinAmount.String())
+	}
+	if filter.MaxAmount != nil {
+		where("amount <= $%d", filter.MaxAmount.String())
+	}
+	if filter.From != nil {
+		where("processed_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("processed_at < $%d", *filter.To)
+	}
+	if filter.FailedRuleID != "" {
+		failed, err := json.Marshal([]map[string]string{{"rule_id": filter.FailedRuleID, "status": "FAILED"}})
+		if err != nil {
+			return nil, fmt.Errorf("encode rule filter: %w", err)
+		}
+		where("rules @> $%d::jsonb", string(failed))
+	}
+
+	column, ok := resultSortColumns[query.SortBy]
+	if !ok {
+		column = resultSortColumns[models.ResultSortProcessedAt]
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if after := query.After; after != nil {
+		var value interface{} = after.ProcessedAt
+		switch query.SortBy {
+		case models.ResultSortRiskScore:
+			value = after.RiskScore
+		case models.ResultSortAmount:
+			value = after.Amount.String()
+		}
+		where("("+column+", id) "+comparison+" ($%d, $%d)", value, after.ID)
+	}
+
+	statement := `SELECT ` + resultColumns + ` FROM validation_results WHERE ` + strings.Join(conditions, " AND ")
+	statement += fmt.Sprintf(` ORDER BY %s %s, id %s`, column, direction, direction)
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		statement += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search validation results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*models.ValidationResult, 0)
+	for rows.Next() {
+		result, err := scanResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("search validation results: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search validation results: %w", err)
+	}
+
+	return results, nil
+}
+
+func scanResult(row rowScanner) (*models.ValidationResult, error) {
+	var (
+		result         models.ValidationResult
+		amount         string
+		status         string
+		rules          []byte
+		metadata       []byte
+		processingTime int64
+		requestedBy    models.Caller
+	)
+
+	err := row.Scan(
+		&result.ID,
+		&result.TransactionID,
+		&result.CounterpartyID,
+		&amount,
+		&result.Currency,
+		&status,
+		&rules,
+		&result.ErrorCode,
+		&result.ErrorMessage,
+		&result.ProcessedAt,
+		&processingTime,
+		&result.RiskScore,
+		&metadata,
+		&requestedBy.Subject,
+		&requestedBy.Method,
+		&result.TenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Status = models.ValidationStatus(status)
+	result.ProcessingTime = time.Duration(processingTime)
+	if requestedBy.Subject != "" {
+		result.RequestedBy = &requestedBy
+	}
+
+	if result.Amount, err = models.ParseAmount(amount); err != nil {
+		return nil, fmt.Errorf("decode amount: %w", err)
+	}
+	if err := json.Unmarshal(rules, &result.Rules); err != nil {
+		return nil, fmt.Errorf("decode rules: %w", err)
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &result.Metadata); err != nil {
+			return nil, fmt.Errorf("decode metadata: %w", err)
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// idempotencySchemaStatements are applied in order on startup and must be idempotent
+var idempotencySchemaStatements = []string{
+	`CREATE TABLE IF NOT EXISTS validation_idempotency (
+		key           TEXT PRIMARY KEY,
+		fingerprint   TEXT NOT NULL,
+		validation_id TEXT NOT NULL DEFAULT '',
+		created_at    TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_idempotency_created_at
+		ON validation_idempotency (created_at)`,
+}
+
+// reserveAttempts bounds retries when a conflicting key is released between the
+// insert and the lookup
+const reserveAttempts = 3
+
+// PostgresIdempotencyRepository stores idempotency keys in PostgreSQL
+type PostgresIdempotencyRepository struct {
+	db  *sql.DB
+	ttl time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresIdempotencyRepository creates an idempotency repository whose keys expire
+// after ttl and ensures its schema exists
+func NewPostgresIdempotencyRepository(ctx context.Context, db *sql.DB, ttl time.Duration) (*PostgresIdempotencyRepository, error) {
+	for _, stmt := range idempotencySchemaStatements {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("apply idempotency schema: %w", err)
+		}
+	}
+
+	return &PostgresIdempotencyRepository{db: db, ttl: ttl, lastSweep: time.Now()}, nil
+}
+
+// Reserve claims the key, taking over expired records, or returns the live record holding it
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, bool, error) {
+	r.sweep(ctx)
+
+	for attempt := 0; attempt < reserveAttempts; attempt++ {
+		now := time.Now()
+		res, err := r.db.ExecContext(ctx, `
+			INSERT INTO validation_idempotency (key, fingerprint, validation_id, created_at)
+			VALUES ($1, $2, '', $3)
+			ON CONFLICT (key) DO UPDATE SET
+				fingerprint   = EXCLUDED.fingerprint,
+				validation_id = '',
+				created_at    = EXCLUDED.created_at
+			WHERE validation_idempotency.created_at < $4
+			   OR (validation_idempotency.validation_id = '' AND validation_idempotency.created_at < $5)`,
+			record.Key,
+			record.Fingerprint,
+			record.CreatedAt,
+			now.Add(-r.ttl),
+			now.Add(-pendingReservationTimeout),
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("reserve idempotency key: %w", err)
+		}
+
+		if affected, err := res.RowsAffected(); err == nil && affected == 1 {
+			return nil, true, nil
+		}
+
+		var existing models.IdempotencyRecord
+		err = r.db.QueryRowContext(ctx, `
+			SELECT key, fingerprint, validation_id, created_at
+			FROM validation_idempotency
+			WHERE key = $1`, record.Key,
+		).Scan(&existing.Key, &existing.Fingerprint, &existing.ValidationID, &existing.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released since the insert; try again
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("load idempotency key: %w", err)
+		}
+
+		return &existing, false, nil
+	}
+
+	return nil, false, fmt.Errorf("reserve idempotency key %s: contended", record.Key)
+}
+
+// Complete records the validation ID for the key
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, key, validationID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE validation_idempotency SET validation_id = $2 WHERE key = $1`, key, validationID)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release removes a reservation that has no result
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM validation_idempotency WHERE key = $1 AND validation_id = ''`, key)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// sweep deletes expired keys at most once per sweep interval
+func (r *PostgresIdempotencyRepository) sweep(ctx context.Context) {
+	r.mu.Lock()
+	if time.Since(r.lastSweep) < idempotencySweepInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastSweep = time.Now()
+	r.mu.Unlock()
+
+	// Best effort; expired keys are also replaced on reservation
+	_, _ = r.db.ExecContext(ctx, `
+		DELETE FROM validation_idempotency WHERE created_at < $1`, time.Now().Add(-r.ttl))
+}
@@ -0,0 +1,233 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresResultRepository_SaveAndFind(t *testing.T) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	db, err := OpenPostgres(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPostgresResultRepository(ctx, db)
+	require.NoError(t, err)
+
+	result := &models.ValidationResult{
+		ID:             "val-integration-" + time.Now().Format("150405.000000000"),
+		TransactionID:  "txn-integration",
+		Status:         models.ValidationStatusFailed,
+		Rules:          []models.RuleResult{{RuleID: "amount-limit", Status: "FAILED"}},
+		ErrorCode:      "VALIDATION_FAILED",
+		ProcessedAt:    time.Now().UTC().Truncate(time.Microsecond),
+		ProcessingTime: 42 * time.Millisecond,
+		RiskScore:      100,
+		RequestedBy:    &models.Caller{Subject: "transaction-service", Method: "api_key"},
+		TenantID:       "retail",
+	}
+	require.NoError(t, repo.Save(ctx, result))
+
+	found, err := repo.FindByID(ctx, "retail", result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.TransactionID, found.TransactionID)
+	assert.Equal(t, result.Status, found.Status)
+	assert.Equal(t, result.ProcessingTime, found.ProcessingTime)
+	assert.Equal(t, result.RiskScore, found.RiskScore)
+	assert.Equal(t, result.RequestedBy, found.RequestedBy)
+	assert.Equal(t, "retail", found.TenantID)
+	assert.Len(t, found.Rules, 1)
+
+	_, err = repo.FindByID(ctx, "", result.ID)
+	assert.ErrorIs(t, err, ErrNotFound, "results are not visible to other tenants")
+
+	_, err = repo.FindByID(ctx, "retail", "val-does-not-exist")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPostgresJobRepository_SaveFindAndListPending(t *testing.T) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	db, err := OpenPostgres(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPostgresJobRepository(ctx, db)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	job := &models.Job{
+		ID:          "job-integration-" + now.Format("150405.000000000"),
+		Status:      models.JobStatusRunning,
+		Total:       2,
+		Processed:   1,
+		Summary:     models.BatchSummary{Total: 1, Passed: 1},
+		CallbackURL: "https://example.com/hook",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Items:       []models.JobItem{{Error: "bad json"}, {Error: "bad json"}},
+		RequestedBy: &models.Caller{Subject: "batch-client", Method: "jwt"},
+		TenantID:    "retail",
+	}
+	require.NoError(t, repo.Save(ctx, job))
+	require.NoError(t, repo.AppendResults(ctx, job.ID, []models.BatchItemResult{{Index: 0, Error: "from first run"}}))
+	require.NoError(t, repo.AppendResults(ctx, job.ID, []models.BatchItemResult{{Index: 0, Error: "Invalid request format"}}))
+
+	found, err := repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.Status, found.Status)
+	assert.Equal(t, job.Processed, found.Processed)
+	assert.Equal(t, job.Summary, found.Summary)
+	assert.Nil(t, found.Items, "FindByID leaves the items unread")
+	assert.Nil(t, found.Results, "FindByID leaves the results unread")
+	assert.Nil(t, found.CompletedAt)
+	assert.Equal(t, job.RequestedBy, found.RequestedBy)
+	assert.Equal(t, job.TenantID, found.TenantID)
+
+	found, err = repo.Load(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Len(t, found.Items, 2)
+	require.Len(t, found.Results, 1)
+	assert.Equal(t, "Invalid request format", found.Results[0].Error)
+
+	pending, err := repo.ListPending(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+	}
+	assert.Contains(t, ids, job.ID)
+
+	require.NoError(t, repo.AppendResults(ctx, job.ID, []models.BatchItemResult{{Index: 1, Error: "Invalid request format"}}))
+	job.Status = models.JobStatusCompleted
+	job.Processed = 2
+	job.CompletedAt = &now
+	require.NoError(t, repo.Save(ctx, job))
+
+	found, err = repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.CompletedAt)
+
+	results, err := repo.FindResults(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2, "saving the job keeps its results")
+	assert.Equal(t, 1, results[1].Index)
+
+	_, err = repo.FindByID(ctx, "job-does-not-exist")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPostgresIdempotencyRepository_ReserveAndComplete(t *testing.T) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	db, err := OpenPostgres(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPostgresIdempotencyRepository(ctx, db, time.Hour)
+	require.NoError(t, err)
+
+	key := "txn:integration-" + time.Now().Format("150405.000000000")
+	record := &models.IdempotencyRecord{Key: key, Fingerprint: "abc", CreatedAt: time.Now()}
+
+	_, reserved, err := repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	existing, reserved, err := repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Empty(t, existing.ValidationID)
+
+	require.NoError(t, repo.Complete(ctx, key, "val-1"))
+	existing, reserved, err = repo.Reserve(ctx, record)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "abc", existing.Fingerprint)
+	assert.Equal(t, "val-1", existing.ValidationID)
+
+	require.NoError(t, repo.Release(ctx, key))
+	assert.ErrorIs(t, repo.Complete(ctx, "txn:does-not-exist", "val-2"), ErrNotFound)
+}
+
+func TestPostgresResultRepository_Search(t *testing.T) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	db, err := OpenPostgres(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo, err := NewPostgresResultRepository(ctx, db)
+	require.NoError(t, err)
+
+	// A counterparty unique to this run isolates the test from other rows
+	counterparty := "cp-search-" + time.Now().Format("150405.000000000")
+	base := time.Now().UTC().Truncate(time.Microsecond)
+	for i, amount := range []string{"100", "20000", "500"} {
+		result := &models.ValidationResult{
+			ID:             fmt.Sprintf("%s-val-%d", counterparty, i),
+			TransactionID:  fmt.Sprintf("%s-txn-%d", counterparty, i),
+			CounterpartyID: counterparty,
+			Amount:         models.MustParseAmount(amount),
+			Currency:       "USD",
+			Status:         models.ValidationStatusPassed,
+			Rules:          []models.RuleResult{{RuleID: "amount-limit", Status: "PASSED"}},
+			ProcessedAt:    base.Add(time.Duration(i) * time.Second),
+		}
+		if i == 1 {
+			result.Status = models.ValidationStatusFailed
+			result.Rules[0].Status = "FAILED"
+		}
+		require.NoError(t, repo.Save(ctx, result))
+	}
+
+	filter := models.ResultFilter{CounterpartyID: counterparty}
+
+	results, err := repo.Search(ctx, models.ResultQuery{Filter: filter, SortBy: models.ResultSortAmount, Descending: true, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, counterparty+"-val-1", results[0].ID)
+	assert.Equal(t, "20000", results[0].Amount.String())
+
+	results, err = repo.Search(ctx, models.ResultQuery{Filter: filter, SortBy: models.ResultSortAmount, Descending: true,
+		After: &models.ResultCursor{ID: results[1].ID, Amount: results[1].Amount}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, counterparty+"-val-0", results[0].ID)
+
+	filter.FailedRuleID = "amount-limit"
+	results, err = repo.Search(ctx, models.ResultQuery{Filter: filter})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, models.ValidationStatusFailed, results[0].Status)
+
+	results, err = repo.Search(ctx, models.ResultQuery{TenantID: "retail", Filter: filter})
+	require.NoError(t, err)
+	assert.Empty(t, results, "other tenants' results are not searched")
+}
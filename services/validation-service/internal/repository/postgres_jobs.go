@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// jobSchemaStatements are applied in order on startup and must be idempotent
+var jobSchemaStatements = []string{
+	`CREATE TABLE IF NOT EXISTS validation_jobs (
+		id                TEXT PRIMARY KEY,
+		status            TEXT NOT NULL,
+		total             INTEGER NOT NULL,
+		processed         INTEGER NOT NULL DEFAULT 0,
+		summary           JSONB NOT NULL DEFAULT '{}',
+		callback_url      TEXT NOT NULL DEFAULT '',
+		callback_status   TEXT NOT NULL DEFAULT '',
+		callback_attempts INTEGER NOT NULL DEFAULT 0,
+		callback_error    TEXT NOT NULL DEFAULT '',
+		items             JSONB NOT NULL,
+		created_at        TIMESTAMPTZ NOT NULL,
+		updated_at        TIMESTAMPTZ NOT NULL,
+		completed_at      TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_validation_jobs_pending
+		ON validation_jobs (created_at)
+		WHERE status <> 'COMPLETED' OR callback_status = 'PENDING'`,
+	`ALTER TABLE validation_jobs
+		ADD COLUMN IF NOT EXISTS requested_by JSONB`,
+	`ALTER TABLE validation_jobs
+		ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE IF NOT EXISTS validation_job_results (
+		job_id     TEXT NOT NULL REFERENCES validation_jobs (id) ON DELETE CASCADE,
+		item_index INTEGER NOT NULL,
+		result     JSONB NOT NULL,
+		PRIMARY KEY (job_id, item_index)
+	)`,
+}
+
+// jobStatusColumns are the validation_jobs columns describing a job's status and
+// progress, as returned by FindByID
+const jobStatusColumns = `id, status, total, processed, summary, callback_url, callback_status,
+	callback_attempts, callback_error, created_at, updated_at, completed_at, requested_by,
+	tenant_id`
+
+// jobColumns lists the validation_jobs columns written by Save
+const jobColumns = jobStatusColumns + `, items`
+
+// selectJobStatus selects jobs in the scan order of scanJob without data
+const selectJobStatus = `SELECT ` + jobStatusColumns + ` FROM validation_jobs`
+
+// selectJobs selects jobs in the scan order of scanJob with data: jobColumns followed
+// by the job's results
+const selectJobs = `SELECT ` + jobColumns + `,
+	(SELECT COALESCE(jsonb_agg(r.result ORDER BY r.item_index), '[]')
+		FROM validation_job_results r WHERE r.job_id = validation_jobs.id)
+	FROM validation_jobs`
+
+// PostgresJobRepository stores asynchronous validation jobs in PostgreSQL
+type PostgresJobRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresJobRepository creates a job repository and ensures its schema exists
+func NewPostgresJobRepository(ctx context.Context, db *sql.DB) (*PostgresJobRepository, error) {
+	for _, stmt := range jobSchemaStatements {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("apply job schema: %w", err)
+		}
+	}
+
+	return &PostgresJobRepository{db: db}, nil
+}
+
+// Save inserts or updates a job. Items do not change after submission, so they are
+// only written when the job is inserted; results are written by AppendResults.
+func (r *PostgresJobRepository) Save(ctx context.Context, job *models.Job) error {
+	summary, err := json.Marshal(job.Summary)
+	if err != nil {
+		return fmt.Errorf("encode summary: %w", err)
+	}
+
+	items, err := json.Marshal(job.Items)
+	if err != nil {
+		return fmt.Errorf("encode items: %w", err)
+	}
+
+	// NULL rather than "null" when the job was submitted anonymously
+	var requestedBy []byte
+	if job.RequestedBy != nil {
+		if requestedBy, err = json.Marshal(job.RequestedBy); err != nil {
+			return fmt.Errorf("encode requested_by: %w", err)
+		}
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO validation_jobs (`+jobColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			status            = EXCLUDED.status,
+			total             = EXCLUDED.total,
+			processed         = EXCLUDED.processed,
+			summary           = EXCLUDED.summary,
+			callback_url      = EXCLUDED.callback_url,
+			callback_status   = EXCLUDED.callback_status,
+			callback_attempts = EXCLUDED.callback_attempts,
+			callback_error    = EXCLUDED.callback_error,
+			updated_at        = EXCLUDED.updated_at,
+			completed_at      = EXCLUDED.completed_at,
+			requested_by      = EXCLUDED.requested_by,
+			tenant_id         = EXCLUDED.tenant_id`,
+		job.ID,
+		string(job.Status),
+		job.Total,
+		job.Processed,
+		summary,
+		job.CallbackURL,
+		string(job.CallbackStatus),
+		job.CallbackAttempts,
+		job.CallbackError,
+		job.CreatedAt,
+		job.UpdatedAt,
+		job.CompletedAt,
+		requestedBy,
+		job.TenantID,
+		items,
+	)
+	if err != nil {
+		return fmt.Errorf("save job %s: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+// AppendResults upserts the results in one statement, so that each chunk of a job
+// costs a write of that chunk only
+func (r *PostgresJobRepository) AppendResults(ctx context.Context, id string, results []models.BatchItemResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("encode results: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO validation_job_results (job_id, item_index, result)
+		SELECT $1, (r->>'index')::INTEGER, r
+		FROM jsonb_array_elements($2::jsonb) AS r
+		ON CONFLICT (job_id, item_index) DO UPDATE SET result = EXCLUDED.result`,
+		id, encoded)
+	if err != nil {
+		return fmt.Errorf("save results of job %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// FindByID loads a job's status and progress, leaving its items and results unread
+func (r *PostgresJobRepository) FindByID(ctx context.Context, id string) (*models.Job, error) {
+	return r.find(ctx, id, false)
+}
+
+// Load loads a job with its items and results
+func (r *PostgresJobRepository) Load(ctx context.Context, id string) (*models.Job, error) {
+	return r.find(ctx, id, true)
+}
+
+func (r *PostgresJobRepository) find(ctx context.Context, id string, withData bool) (*models.Job, error) {
+	query := selectJobStatus
+	if withData {
+		query = selectJobs
+	}
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query+` WHERE id = $1`, id), withData)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load job %s: %w", id, err)
+	}
+
+	return job, nil
+}
+
+// FindResults loads the results stored for a job's items
+func (r *PostgresJobRepository) FindResults(ctx context.Context, id string) ([]models.BatchItemResult, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT result FROM validation_job_results
+		WHERE job_id = $1
+		ORDER BY item_index`, id)
+	if err != nil {
+		return nil, fmt.Errorf("load results of job %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	results := make([]models.BatchItemResult, 0)
+	for rows.Next() {
+		var (
+			encoded []byte
+			result  models.BatchItemResult
+		)
+		if err := rows.Scan(&encoded); err != nil {
+			return nil, fmt.Errorf("load results of job %s: %w", id, err)
+		}
+		if err := json.Unmarshal(encoded, &result); err != nil {
+			return nil, fmt.Errorf("decode result of job %s: %w", id, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// ListPending loads unfinished jobs and jobs with a pending callback, oldest first
+func (r *PostgresJobRepository) ListPending(ctx context.Context) ([]*models.Job, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		`+selectJobs+`
+		WHERE status <> 'COMPLETED' OR callback_status = 'PENDING'
+		ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list pending jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows, true)
+		if err != nil {
+			return nil, fmt.Errorf("list pending jobs: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJob reads a row selected by selectJobStatus, or by selectJobs when withData is set
+func scanJob(row rowScanner, withData bool) (*models.Job, error) {
+	var (
+		job            models.Job
+		status         string
+		callbackStatus string
+		summary        []byte
+		items          []byte
+		results        []byte
+		completedAt    sql.NullTime
+		requestedBy    []byte
+	)
+
+	dest := []interface{}{
+		&job.ID,
+		&status,
+		&job.Total,
+		&job.Processed,
+		&summary,
+		&job.CallbackURL,
+		&callbackStatus,
+		&job.CallbackAttempts,
+		&job.CallbackError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&completedAt,
+		&requestedBy,
+		&job.TenantID,
+	}
+	if withData {
+		dest = append(dest, &items, &results)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	job.Status = models.JobStatus(status)
+	job.CallbackStatus = models.CallbackStatus(callbackStatus)
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+
+	if err := json.Unmarshal(summary, &job.Summary); err != nil {
+		return nil, fmt.Errorf("decode summary: %w", err)
+	}
+	if len(requestedBy) > 0 {
+		if err := json.Unmarshal(requestedBy, &job.RequestedBy); err != nil {
+			return nil, fmt.Errorf("decode requested_by: %w", err)
+		}
+	}
+	if withData {
+		if err := json.Unmarshal(items, &job.Items); err != nil {
+			return nil, fmt.Errorf("decode items: %w", err)
+		}
+		if err := json.Unmarshal(results, &job.Results); err != nil {
+			return nil, fmt.Errorf("decode results: %w", err)
+		}
+	}
+
+	return &job, nil
+}
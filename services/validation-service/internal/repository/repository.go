@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ValidationResultRepository persists and retrieves validation results, partitioned by
+// tenant: results are only ever returned to queries for the tenant that owns them
+type ValidationResultRepository interface {
+	// Save stores a validation result, replacing any existing result with the same ID
+	Save(ctx context.Context, result *models.ValidationResult) error
+
+	// FindByID returns the tenant's validation result with the given ID or ErrNotFound
+	FindByID(ctx context.Context, tenantID, id string) (*models.ValidationResult, error)
+
+	// Search returns up to query.Limit of query.TenantID's results matching the filter,
+	// in query order
+	Search(ctx context.Context, query models.ResultQuery) ([]*models.ValidationResult, error)
+}
+
+// JobRepository persists asynchronous validation jobs, including their items and results
+type JobRepository interface {
+	// Save stores a job, replacing any existing job with the same ID. The job's
+	// results are stored with AppendResults and are left unchanged.
+	Save(ctx context.Context, job *models.Job) error
+
+	// AppendResults stores results of a saved job's items following those already
+	// stored. A result for an item that already has one replaces it.
+	AppendResults(ctx context.Context, id string, results []models.BatchItemResult) error
+
+	// FindByID returns the status and progress of the job with the given ID, without
+	// its items and results, or ErrNotFound
+	FindByID(ctx context.Context, id string) (*models.Job, error)
+
+	// Load returns the job with the given ID including its items and results, or
+	// ErrNotFound
+	Load(ctx context.Context, id string) (*models.Job, error)
+
+	// FindResults returns the results stored for the job's items, in item order
+	FindResults(ctx context.Context, id string) ([]models.BatchItemResult, error)
+
+	// ListPending returns jobs that are not completed or whose callback is still
+	// pending, oldest first, including their items and results
+	ListPending(ctx context.Context) ([]*models.Job, error)
+}
+
+// IdempotencyRepository tracks which request and result each idempotency key belongs to
+type IdempotencyRepository interface {
+	// Reserve claims record.Key for a new request. If the key is already held it
+	// returns the existing record and false; expired records are replaced.
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, bool, error)
+
+	// Complete records the validation result for a reserved key
+	Complete(ctx context.Context, key, validationID string) error
+
+	// Release drops a reservation that did not produce a result, so the request can be retried
+	Release(ctx context.Context, key string) error
+}
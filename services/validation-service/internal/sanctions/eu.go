@@ -0,0 +1,106 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Columns of the EU consolidated financial sanctions list CSV used for screening
+const (
+	euColumnLogicalID = "Entity_LogicalId"
+	euColumnType      = "Entity_SubjectType"
+	euColumnProgramme = "Entity_Regulation_Programme"
+	euColumnWholeName = "NameAlias_WholeName"
+)
+
+// ParseEUCSV parses the EU consolidated financial sanctions list in its published
+// semicolon-separated CSV format. The file has one row per name, address, birth date
+// or identification of an entity; rows are grouped by Entity_LogicalId and the first
+// name of each entity becomes its primary name.
+func ParseEUCSV(r io.Reader, listName string) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("parse EU consolidated list CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, required := range []string{euColumnLogicalID, euColumnWholeName} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("parse EU consolidated list CSV: missing column %s", required)
+		}
+	}
+
+	column := func(fields []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	entries := make([]Entry, 0)
+	positions := make(map[string]int)
+	seenNames := make(map[string]map[string]bool)
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse EU consolidated list CSV: %w", err)
+		}
+
+		id := column(fields, euColumnLogicalID)
+		if id == "" {
+			continue
+		}
+
+		i, ok := positions[id]
+		if !ok {
+			i = len(entries)
+			positions[id] = i
+			seenNames[id] = make(map[string]bool)
+			entries = append(entries, Entry{
+				List: listName,
+				UID:  id,
+				Type: column(fields, euColumnType),
+			})
+			if programme := column(fields, euColumnProgramme); programme != "" {
+				entries[i].Programs = []string{programme}
+			}
+		}
+
+		name := column(fields, euColumnWholeName)
+		if name == "" || seenNames[id][name] {
+			continue
+		}
+		seenNames[id][name] = true
+
+		if entries[i].Name == "" {
+			entries[i].Name = name
+		} else {
+			entries[i].Aliases = append(entries[i].Aliases, name)
+		}
+	}
+
+	named := entries[:0]
+	for _, entry := range entries {
+		if entry.Name != "" {
+			named = append(named, entry)
+		}
+	}
+
+	return named, nil
+}
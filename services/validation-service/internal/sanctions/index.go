@@ -0,0 +1,118 @@
+package sanctions
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gtrs/validation-service/internal/namematch"
+)
+
+// Entry is a sanctioned party from a published list
+type Entry struct {
+	List     string   `json:"list"`
+	UID      string   `json:"uid"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Type     string   `json:"type,omitempty"` // e.g. Individual, Entity, Vessel
+	Programs []string `json:"programs,omitempty"`
+}
+
+// Match is an entry whose name or alias matched a screened name
+type Match struct {
+	Entry       Entry   `json:"entry"`
+	MatchedName string  `json:"matched_name"`
+	Score       float64 `json:"score"`
+}
+
+// Index looks up entries by normalised name and alias
+type Index struct {
+	entries []Entry
+	names   []indexedName
+	byKey   map[string][]int // normalised name -> positions in names
+}
+
+type indexedName struct {
+	entry  int
+	name   string
+	tokens []string
+}
+
+// NewIndex builds an index over the names and aliases of the given entries
+func NewIndex(entries []Entry) *Index {
+	index := &Index{
+		entries: entries,
+		byKey:   make(map[string][]int),
+	}
+
+	for i, entry := range entries {
+		names := append([]string{entry.Name}, entry.Aliases...)
+		for _, name := range names {
+			tokens := namematch.Tokens(name)
+			if len(tokens) == 0 {
+				continue
+			}
+			key := strings.Join(tokens, " ")
+			index.byKey[key] = append(index.byKey[key], len(index.names))
+			index.names = append(index.names, indexedName{entry: i, name: name, tokens: tokens})
+		}
+	}
+
+	return index
+}
+
+// Len returns the number of indexed entries
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// Screen returns the entries whose name or alias matches the screened name, best
+// score first. A nil matcher (or an exact one) compares normalised names for equality;
+// other matchers score every indexed name and keep those at or above their threshold.
+func (idx *Index) Screen(name string, matcher *namematch.Matcher) []Match {
+	tokens := namematch.Tokens(name)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	best := make(map[int]Match)
+	consider := func(position int, score float64) {
+		indexed := idx.names[position]
+		if current, ok := best[indexed.entry]; ok && current.Score >= score {
+			return
+		}
+		best[indexed.entry] = Match{
+			Entry:       idx.entries[indexed.entry],
+			MatchedName: indexed.name,
+			Score:       score,
+		}
+	}
+
+	if matcher == nil || matcher.Algorithm() == namematch.AlgorithmExact {
+		for _, position := range idx.byKey[strings.Join(tokens, " ")] {
+			consider(position, 1)
+		}
+	} else {
+		for position, indexed := range idx.names {
+			if score := matcher.ScoreTokens(tokens, indexed.tokens); score >= matcher.Threshold() {
+				consider(position, score)
+			}
+		}
+	}
+
+	if len(best) == 0 {
+		return nil
+	}
+
+	matches := make([]Match, 0, len(best))
+	for _, match := range best {
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Entry.UID < matches[j].Entry.UID
+	})
+
+	return matches
+}
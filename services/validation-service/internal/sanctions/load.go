@@ -0,0 +1,111 @@
+package sanctions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Supported list file formats
+const (
+	FormatOFACXML = "OFAC_SDN_XML"
+	FormatOFACCSV = "OFAC_SDN_CSV"
+	FormatEUCSV   = "EU_CSV"
+)
+
+// Source describes a sanctions list file on disk
+type Source struct {
+	Name    string // list name reported in matches, e.g. "OFAC SDN"
+	Format  string
+	Path    string
+	AltPath string // OFAC alt.csv, only for FormatOFACCSV
+}
+
+// cachedList avoids re-parsing unchanged list files every time rules are reloaded
+type cachedList struct {
+	modTime    time.Time
+	size       int64
+	altModTime time.Time
+	entries    []Entry
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[Source]cachedList)
+)
+
+// Load reads and parses a list file. Parsed lists are cached until the file changes.
+func Load(source Source) ([]Entry, error) {
+	info, err := os.Stat(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("sanctions list %s: %w", source.Path, err)
+	}
+
+	var altModTime time.Time
+	if source.AltPath != "" {
+		altInfo, err := os.Stat(source.AltPath)
+		if err != nil {
+			return nil, fmt.Errorf("sanctions list %s: %w", source.AltPath, err)
+		}
+		altModTime = altInfo.ModTime()
+	}
+
+	cacheMu.Lock()
+	cached, ok := cache[source]
+	cacheMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() && cached.altModTime.Equal(altModTime) {
+		return cached.entries, nil
+	}
+
+	entries, err := parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMu.Lock()
+	cache[source] = cachedList{
+		modTime:    info.ModTime(),
+		size:       info.Size(),
+		altModTime: altModTime,
+		entries:    entries,
+	}
+	cacheMu.Unlock()
+
+	return entries, nil
+}
+
+func parseSource(source Source) ([]Entry, error) {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("sanctions list %s: %w", source.Path, err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	switch source.Format {
+	case FormatOFACXML:
+		entries, err = ParseOFACXML(file, source.Name)
+	case FormatOFACCSV:
+		var alt io.Reader
+		if source.AltPath != "" {
+			altFile, err := os.Open(source.AltPath)
+			if err != nil {
+				return nil, fmt.Errorf("sanctions list %s: %w", source.AltPath, err)
+			}
+			defer altFile.Close()
+			alt = altFile
+		}
+		entries, err = ParseOFACCSV(file, alt, source.Name)
+	case FormatEUCSV:
+		entries, err = ParseEUCSV(file, source.Name)
+	default:
+		return nil, fmt.Errorf("sanctions list %s: unsupported format %q", source.Path, source.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sanctions list %s: %w", source.Path, err)
+	}
+
+	return entries, nil
+}
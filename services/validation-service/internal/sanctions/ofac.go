@@ -0,0 +1,168 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ofacNull is the placeholder OFAC uses for empty CSV fields
+const ofacNull = "-0-"
+
+type ofacSDNList struct {
+	Entries []ofacSDNEntry `xml:"sdnEntry"`
+}
+
+type ofacSDNEntry struct {
+	UID       string    `xml:"uid"`
+	FirstName string    `xml:"firstName"`
+	LastName  string    `xml:"lastName"`
+	SDNType   string    `xml:"sdnType"`
+	Programs  []string  `xml:"programList>program"`
+	AKAs      []ofacAKA `xml:"akaList>aka"`
+}
+
+type ofacAKA struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+// ParseOFACXML parses the OFAC SDN list in its published XML format (sdn.xml)
+func ParseOFACXML(r io.Reader, listName string) ([]Entry, error) {
+	var list ofacSDNList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("parse OFAC SDN XML: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(list.Entries))
+	for _, sdn := range list.Entries {
+		entry := Entry{
+			List:     listName,
+			UID:      strings.TrimSpace(sdn.UID),
+			Name:     joinName(sdn.FirstName, sdn.LastName),
+			Type:     strings.TrimSpace(sdn.SDNType),
+			Programs: sdn.Programs,
+		}
+		for _, aka := range sdn.AKAs {
+			if alias := joinName(aka.FirstName, aka.LastName); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// ParseOFACCSV parses the OFAC SDN list in its published CSV format. sdn is the
+// primary file (sdn.csv); alt is the optional alternate names file (alt.csv).
+func ParseOFACCSV(sdn io.Reader, alt io.Reader, listName string) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	positions := make(map[string]int)
+
+	err := readOFACCSV(sdn, func(fields []string) {
+		// ent_num, SDN_Name, SDN_Type, Program, ...
+		if len(fields) < 4 {
+			return
+		}
+		entry := Entry{
+			List:     listName,
+			UID:      ofacField(fields[0]),
+			Type:     ofacField(fields[2]),
+			Programs: splitOFACPrograms(ofacField(fields[3])),
+		}
+		entry.Name = ofacCSVName(ofacField(fields[1]), entry.Type)
+		if entry.UID == "" || entry.Name == "" {
+			return
+		}
+		positions[entry.UID] = len(entries)
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse OFAC SDN CSV: %w", err)
+	}
+
+	if alt == nil {
+		return entries, nil
+	}
+
+	err = readOFACCSV(alt, func(fields []string) {
+		// ent_num, alt_num, alt_type, alt_name, alt_remarks
+		if len(fields) < 4 {
+			return
+		}
+		i, ok := positions[ofacField(fields[0])]
+		if !ok {
+			return
+		}
+		if alias := ofacCSVName(ofacField(fields[3]), entries[i].Type); alias != "" {
+			entries[i].Aliases = append(entries[i].Aliases, alias)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse OFAC alternate names CSV: %w", err)
+	}
+
+	return entries, nil
+}
+
+func readOFACCSV(r io.Reader, row func(fields []string)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row(fields)
+	}
+}
+
+func ofacField(value string) string {
+	value = strings.TrimSpace(value)
+	if value == ofacNull {
+		return ""
+	}
+	return value
+}
+
+// ofacCSVName turns the "LAST, First" form used for individuals into "First LAST"
+func ofacCSVName(name, sdnType string) string {
+	if !strings.EqualFold(sdnType, "individual") {
+		return name
+	}
+	last, first, found := strings.Cut(name, ",")
+	if !found {
+		return name
+	}
+	return joinName(first, last)
+}
+
+// splitOFACPrograms splits program lists such as "IRAN] [SDGT"
+func splitOFACPrograms(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var programs []string
+	for _, program := range strings.Split(value, "] [") {
+		if program = strings.Trim(program, "[] "); program != "" {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+func joinName(first, last string) string {
+	return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+}
@@ -0,0 +1,120 @@
+package sanctions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/namematch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestdata(t *testing.T, name string) *os.File {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestParseOFACXML(t *testing.T) {
+	entries, err := ParseOFACXML(openTestdata(t, "sdn.xml"), "OFAC SDN")
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "AEROCARIBBEAN AIRLINES", entries[0].Name)
+	assert.Equal(t, []string{"AERO-CARIBBEAN"}, entries[0].Aliases)
+	assert.Equal(t, "John Example DOE", entries[1].Name)
+	assert.Equal(t, "Individual", entries[1].Type)
+	assert.Equal(t, []string{"SDGT", "IRAN"}, entries[1].Programs)
+	assert.Equal(t, []string{"Jon DOUGH"}, entries[1].Aliases)
+}
+
+func TestParseOFACCSV(t *testing.T) {
+	entries, err := ParseOFACCSV(openTestdata(t, "sdn.csv"), openTestdata(t, "alt.csv"), "OFAC SDN")
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "36", entries[0].UID)
+	assert.Equal(t, "", entries[0].Type)
+	assert.Equal(t, []string{"AERO-CARIBBEAN"}, entries[0].Aliases)
+	assert.Equal(t, "John Example DOE", entries[1].Name)
+	assert.Equal(t, []string{"SDGT", "IRAN"}, entries[1].Programs)
+	assert.Equal(t, []string{"Jon DOUGH"}, entries[1].Aliases)
+}
+
+func TestParseEUCSV(t *testing.T) {
+	entries, err := ParseEUCSV(openTestdata(t, "eu.csv"), "EU Consolidated")
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "13", entries[0].UID)
+	assert.Equal(t, "Ivan Example", entries[0].Name)
+	assert.Equal(t, []string{"Iwan Exampl"}, entries[0].Aliases)
+	assert.Equal(t, "person", entries[0].Type)
+	assert.Equal(t, []string{"IRQ"}, entries[0].Programs)
+	assert.Equal(t, "Example Trading Company LLC", entries[1].Name)
+}
+
+func TestIndex_Screen(t *testing.T) {
+	entries, err := ParseOFACXML(openTestdata(t, "sdn.xml"), "OFAC SDN")
+	require.NoError(t, err)
+	index := NewIndex(entries)
+
+	matches := index.Screen("aero caribbean", nil)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "36", matches[0].Entry.UID)
+	assert.Equal(t, "AERO-CARIBBEAN", matches[0].MatchedName)
+
+	// Case, spacing and token order are normalised
+	matches = index.Screen("  DOE, john   example ", nil)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "7157", matches[0].Entry.UID)
+	assert.Equal(t, 1.0, matches[0].Score)
+
+	assert.Empty(t, index.Screen("Acme Corporation", nil))
+}
+
+func TestIndex_Screen_Fuzzy(t *testing.T) {
+	entries, err := ParseOFACXML(openTestdata(t, "sdn.xml"), "OFAC SDN")
+	require.NoError(t, err)
+	index := NewIndex(entries)
+
+	matcher, err := namematch.NewMatcher(namematch.AlgorithmJaroWinkler, 0.9)
+	require.NoError(t, err)
+
+	matches := index.Screen("Jon Doe Exampel", matcher)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "7157", matches[0].Entry.UID)
+	assert.Less(t, matches[0].Score, 1.0)
+	assert.GreaterOrEqual(t, matches[0].Score, 0.9)
+
+	assert.Empty(t, index.Screen("Acme Corporation", matcher))
+}
+
+func TestLoad_CachesUntilFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "eu.csv")
+	data, err := os.ReadFile(filepath.Join("testdata", "eu.csv"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	source := Source{Name: "EU", Format: FormatEUCSV, Path: path}
+	first, err := Load(source)
+	require.NoError(t, err)
+	assert.Len(t, first, 2)
+
+	header := "fileGenerationDate;Entity_LogicalId;Entity_SubjectType;NameAlias_WholeName\n"
+	require.NoError(t, os.WriteFile(path, []byte(header+"2024-01-03;99;person;New Person\n"), 0o644))
+
+	second, err := Load(source)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, "New Person", second[0].Name)
+}
+
+func TestLoad_UnsupportedFormat(t *testing.T) {
+	_, err := Load(Source{Name: "X", Format: "PDF", Path: filepath.Join("testdata", "eu.csv")})
+	assert.Error(t, err)
+}
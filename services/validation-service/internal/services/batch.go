@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BatchInput is one entry of a batch. Err is set when the entry could not be
+// decoded into a request; it is reported for that item only.
+type BatchInput struct {
+	Request *models.ValidationRequest
+	Err     error
+}
+
+// ValidateBatch validates the inputs using at most workers concurrent validations
+// and returns one result per input, in input order. Items not started before ctx is
+// cancelled are reported as errors.
+func (s *ValidationService) ValidateBatch(ctx context.Context, inputs []BatchInput, workers int) *models.BatchResult {
+	startTime := time.Now()
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]models.BatchItemResult, len(inputs))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.validateBatchItem(ctx, i, inputs[i])
+			}
+		}()
+	}
+
+	for i := range inputs {
+		if ctx.Err() == nil {
+			select {
+			case indexes <- i:
+				continue
+			case <-ctx.Done():
+			}
+		}
+		results[i] = models.BatchItemResult{
+			Index:   i,
+			Error:   "Batch cancelled",
+			Details: ctx.Err().Error(),
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	batch := &models.BatchResult{
+		Summary: summarizeBatch(results),
+		Results: results,
+	}
+	batch.Summary.ProcessingTime = time.Since(startTime)
+
+	logrus.WithFields(logrus.Fields{
+		"total":           batch.Summary.Total,
+		"passed":          batch.Summary.Passed,
+		"review":          batch.Summary.Review,
+		"failed":          batch.Summary.Failed,
+		"errors":          batch.Summary.Errors,
+		"processing_time": batch.Summary.ProcessingTime,
+	}).Info("Batch validation completed")
+
+	return batch
+}
+
+// validateBatchItem validates a single batch entry, capturing errors in the item result
+func (s *ValidationService) validateBatchItem(ctx context.Context, index int, input BatchInput) models.BatchItemResult {
+	item := models.BatchItemResult{Index: index}
+
+	if input.Err != nil {
+		item.Error = "Invalid request format"
+		item.Details = input.Err.Error()
+		return item
+	}
+
+	item.TransactionID = input.Request.TransactionID
+
+	result, err := s.ValidateTransaction(ctx, input.Request)
+	switch {
+	case errors.Is(err, context.Canceled):
+		item.Error = "Batch cancelled"
+		item.Details = err.Error()
+	case errors.Is(err, ErrInvalidRequest):
+		item.Error = "Invalid request"
+		item.Details = err.Error()
+	case errors.Is(err, ErrIdempotencyConflict), errors.Is(err, ErrIdempotencyInProgress):
+		item.Error = "Idempotency conflict"
+		item.Details = err.Error()
+	case err != nil:
+		item.Error = "Validation processing failed"
+		item.Details = err.Error()
+	default:
+		item.Result = result
+	}
+
+	return item
+}
+
+func summarizeBatch(results []models.BatchItemResult) models.BatchSummary {
+	summary := models.BatchSummary{Total: len(results)}
+
+	for _, item := range results {
+		if item.Result == nil {
+			summary.Errors++
+			continue
+		}
+
+		switch item.Result.Status {
+		case models.ValidationStatusPassed:
+			summary.Passed++
+		case models.ValidationStatusReview:
+			summary.Review++
+		case models.ValidationStatusFailed:
+			summary.Failed++
+		default:
+			summary.Errors++
+		}
+	}
+
+	return summary
+}
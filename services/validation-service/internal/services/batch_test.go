@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationService_ValidateBatch(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	inputs := make([]BatchInput, 0, 101)
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, BatchInput{Request: newRiskRequest(fmt.Sprintf("%d", 999990+i*100))})
+	}
+	inputs = append(inputs, BatchInput{Err: errors.New("bad json")})
+
+	result := service.ValidateBatch(context.Background(), inputs, 8)
+
+	require.Len(t, result.Results, 101)
+	assert.Equal(t, 101, result.Summary.Total)
+	assert.Equal(t, 1, result.Summary.Passed) // only 999990 is within the 1,000,000 limit
+	assert.Equal(t, 99, result.Summary.Failed)
+	assert.Equal(t, 1, result.Summary.Errors)
+	assert.Equal(t, "Invalid request format", result.Results[100].Error)
+
+	for i, item := range result.Results[:100] {
+		assert.Equal(t, i, item.Index)
+		stored, err := service.GetValidationResult(context.Background(), item.Result.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.Result.Status, stored.Status)
+	}
+}
+
+func TestValidationService_ValidateBatch_Cancelled(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	inputs := []BatchInput{{Request: newRiskRequest("100")}, {Request: newRiskRequest("200")}}
+	result := service.ValidateBatch(ctx, inputs, 1)
+
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, 2, result.Summary.Errors)
+	for i, item := range result.Results {
+		assert.Equal(t, i, item.Index)
+		assert.Equal(t, "Batch cancelled", item.Error)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// evaluateStage applies rules of equal priority, which do not depend on each other,
+// concurrently. Results are returned in the order of the rules.
+func (s *ValidationService) evaluateStage(ctx context.Context, stage []models.ValidationRule, set *ruleSet, request *models.ValidationRequest) []models.RuleResult {
+	results := make([]models.RuleResult, len(stage))
+
+	// A lone rule needs no goroutine of its own
+	if len(stage) == 1 {
+		results[0] = s.evaluateRule(ctx, stage[0], set.state[stage[0].ID], request)
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, rule := range stage {
+		wg.Add(1)
+		go func(i int, rule models.ValidationRule) {
+			defer wg.Done()
+			results[i] = s.evaluateRule(ctx, rule, set.state[rule.ID], request)
+		}(i, rule)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// skippedResults records rules that were not evaluated because a blocking rule failed
+func skippedResults(stage []models.ValidationRule, blockedBy string) []models.RuleResult {
+	now := time.Now()
+	results := make([]models.RuleResult, len(stage))
+	for i, rule := range stage {
+		results[i] = models.RuleResult{
+			RuleID:      rule.ID,
+			RuleName:    rule.Name,
+			Status:      "SKIPPED",
+			Message:     fmt.Sprintf("Not evaluated because blocking rule %s failed", blockedBy),
+			ProcessedAt: now,
+		}
+	}
+	return results
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/velocity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// barrierVelocityStore only answers once the expected number of callers are waiting
+// at the same time, so it fails when rules are evaluated one after another
+type barrierVelocityStore struct {
+	mu      sync.Mutex
+	waiting int
+	release chan struct{}
+	callers int
+}
+
+func newBarrierVelocityStore(callers int) *barrierVelocityStore {
+	return &barrierVelocityStore{release: make(chan struct{}), callers: callers}
+}
+
+func (s *barrierVelocityStore) Record(ctx context.Context, key string, event velocity.Event, windows []time.Duration) ([]velocity.Totals, error) {
+	s.mu.Lock()
+	s.waiting++
+	if s.waiting == s.callers {
+		close(s.release)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.release:
+		return make([]velocity.Totals, len(windows)), nil
+	case <-time.After(time.Second):
+		return nil, errors.New("rules were not evaluated concurrently")
+	}
+}
+
+func newPriorityRule(id string, priority int, expression string) models.ValidationRule {
+	rule := newScoredRule(id, "", 0, expression)
+	rule.Priority = priority
+	return rule
+}
+
+func ruleIDs(result *models.ValidationResult) []string {
+	ids := make([]string, 0, len(result.Rules))
+	for _, rule := range result.Rules {
+		ids = append(ids, rule.RuleID)
+	}
+	return ids
+}
+
+func TestValidationService_RulePriorityOrder(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{
+		newPriorityRule("late", 3, "amount > 1"),
+		newPriorityRule("early-b", 1, "amount > 1"),
+		newPriorityRule("early-a", 1, "amount > 1"),
+		newPriorityRule("middle", 2, "amount > 1"),
+	}))
+
+	for i := 0; i < 20; i++ {
+		result, err := service.ValidateTransaction(context.Background(), newRiskRequest("5000"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"early-a", "early-b", "middle", "late"}, ruleIDs(result))
+	}
+}
+
+func TestValidationService_EqualPriorityRulesRunConcurrently(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithVelocityStore(newBarrierVelocityStore(2)))
+
+	limits := map[string]interface{}{
+		"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 3}},
+	}
+	first := newVelocityRule(limits)
+	second := newVelocityRule(limits)
+	second.ID = "device-velocity"
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{first, second}))
+
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-1", "100"))
+	require.NoError(t, err)
+
+	for _, ruleResult := range result.Rules {
+		assert.Equal(t, "PASSED", ruleResult.Status, ruleResult.Message)
+	}
+	assert.Equal(t, []string{"counterparty-velocity", "device-velocity"}, ruleIDs(result))
+}
+
+func TestValidationService_BlockingRuleShortCircuits(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	blocking := newPriorityRule("blocking", 1, "amount > 100")
+	blocking.Blocking = true
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{
+		blocking,
+		newPriorityRule("peer", 1, "amount > 1000"),
+		newPriorityRule("later", 2, "amount > 1"),
+	}))
+
+	result, err := service.ValidateTransaction(context.Background(), newRiskRequest("5000"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"blocking", "peer", "later"}, ruleIDs(result))
+
+	assert.Equal(t, "FAILED", result.Rules[0].Status)
+	// Rules of the same priority were already running and are kept
+	assert.Equal(t, "FAILED", result.Rules[1].Status)
+	assert.Equal(t, "SKIPPED", result.Rules[2].Status)
+	assert.Contains(t, result.Rules[2].Message, "blocking rule blocking failed")
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+}
+
+func TestValidationService_BlockingRulePasses(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	blocking := newPriorityRule("blocking", 1, "amount > 1000000")
+	blocking.Blocking = true
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{
+		blocking,
+		newPriorityRule("later", 2, "amount > 1"),
+	}))
+
+	result, err := service.ValidateTransaction(context.Background(), newRiskRequest("5000"))
+	require.NoError(t, err)
+	assert.Equal(t, "PASSED", result.Rules[0].Status)
+	assert.Equal(t, "FAILED", result.Rules[1].Status)
+}
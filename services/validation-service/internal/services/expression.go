@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// expressionEnv is the set of fields an EXPRESSION rule can reference, e.g.
+// amount > 5000 && counterparty.type == "INDIVIDUAL" && metadata.channel == "ATM"
+type expressionEnv struct {
+	TransactionID string                 `expr:"transaction_id"`
+	Type          string                 `expr:"type"`
+	Amount        float64                `expr:"amount"` // nearest float64; exact limits belong in AMOUNT_LIMIT
+	Currency      string                 `expr:"currency"`
+	Counterparty  expressionCounterparty `expr:"counterparty"`
+	Metadata      map[string]interface{} `expr:"metadata"`
+	Timestamp     time.Time              `expr:"timestamp"`
+}
+
+type expressionCounterparty struct {
+	ID   string `expr:"id"`
+	Name string `expr:"name"`
+	Type string `expr:"type"`
+}
+
+// compileExpressionRule compiles the rule's "expression" config into a boolean program.
+// Unknown fields and non-boolean expressions are rejected here, at load time.
+func compileExpressionRule(rule models.ValidationRule) (*vm.Program, error) {
+	source, ok := rule.Config["expression"].(string)
+	if !ok || strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("%w: %s config expression must be a non-empty string", ErrInvalidRule, rule.Type)
+	}
+
+	if message, present := rule.Config["message"]; present {
+		if _, ok := message.(string); !ok {
+			return nil, fmt.Errorf("%w: %s config message must be a string", ErrInvalidRule, rule.Type)
+		}
+	}
+
+	program, err := expr.Compile(source, expr.Env(expressionEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s config expression does not compile: %v", ErrInvalidRule, rule.Type, err)
+	}
+
+	return program, nil
+}
+
+// validateExpression evaluates a compiled EXPRESSION rule. The expression describes the
+// condition to flag: the rule fails when it evaluates to true.
+func (s *ValidationService) validateExpression(rule models.ValidationRule, program *vm.Program, request *models.ValidationRequest) models.RuleResult {
+	result := models.RuleResult{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Status:   "PASSED",
+	}
+
+	matched, err := evaluateExpression(program, newExpressionEnv(request))
+	if err != nil {
+		result.Status = "ERROR"
+		result.Message = fmt.Sprintf("Expression could not be evaluated: %v", err)
+		return result
+	}
+
+	if matched {
+		result.Status = "FAILED"
+		result.Message = fmt.Sprintf("Transaction matched condition: %s", program.Source().Content())
+		if message, ok := rule.Config["message"].(string); ok && message != "" {
+			result.Message = message
+		}
+	} else {
+		result.Message = "Transaction did not match condition"
+	}
+
+	return result
+}
+
+// evaluateExpression runs a program, converting any runtime panic into an error
+func evaluateExpression(program *vm.Program, env expressionEnv) (matched bool, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	output, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+
+	matched, ok := output.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %T, expected bool", output)
+	}
+	return matched, nil
+}
+
+func newExpressionEnv(request *models.ValidationRequest) expressionEnv {
+	metadata := request.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	return expressionEnv{
+		TransactionID: request.TransactionID,
+		Type:          request.Type,
+		Amount:        request.Amount.Float64(),
+		Currency:      request.Currency,
+		Counterparty: expressionCounterparty{
+			ID:   request.Counterparty.ID,
+			Name: request.Counterparty.Name,
+			Type: request.Counterparty.Type,
+		},
+		Metadata:  metadata,
+		Timestamp: request.Timestamp,
+	}
+}
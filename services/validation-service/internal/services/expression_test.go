@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExpressionRule(expression string) models.ValidationRule {
+	return models.ValidationRule{
+		ID:      "atm-individual",
+		Name:    "Large ATM Withdrawal by Individual",
+		Type:    RuleTypeExpression,
+		Enabled: true,
+		Config: map[string]interface{}{
+			"expression": expression,
+		},
+	}
+}
+
+func findRuleResult(result *models.ValidationResult, ruleID string) *models.RuleResult {
+	for i := range result.Rules {
+		if result.Rules[i].RuleID == ruleID {
+			return &result.Rules[i]
+		}
+	}
+	return nil
+}
+
+func TestValidationService_ExpressionRule(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	_, err := service.CreateRule(context.Background(), newExpressionRule(
+		`amount > 5000 && counterparty.type == "INDIVIDUAL" && metadata.channel == "ATM"`,
+	))
+	require.NoError(t, err)
+
+	request := &models.ValidationRequest{
+		TransactionID: "txn-expr-1",
+		Type:          "WITHDRAWAL",
+		Amount:        models.MustParseAmount("7500"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Jane Doe", Type: "INDIVIDUAL"},
+		Metadata:      map[string]interface{}{"channel": "ATM"},
+	}
+
+	result, err := service.ValidateTransaction(context.Background(), request)
+	require.NoError(t, err)
+	ruleResult := findRuleResult(result, "atm-individual")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "FAILED", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "matched condition")
+
+	// Missing metadata does not match
+	request.Metadata = nil
+	result, err = service.ValidateTransaction(context.Background(), request)
+	require.NoError(t, err)
+	ruleResult = findRuleResult(result, "atm-individual")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "PASSED", ruleResult.Status)
+}
+
+func TestValidationService_ExpressionRule_CustomMessage(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	rule := newExpressionRule(`currency == "USD"`)
+	rule.Config["message"] = "USD payments need manual approval"
+	_, err := service.CreateRule(context.Background(), rule)
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), &models.ValidationRequest{
+		TransactionID: "txn-expr-2",
+		Amount:        models.MustParseAmount("10"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+	})
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "atm-individual")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "USD payments need manual approval", ruleResult.Message)
+}
+
+func TestValidationService_ExpressionRule_RuntimeError(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	_, err := service.CreateRule(context.Background(), newExpressionRule(`metadata.attempts > 3`))
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), &models.ValidationRequest{
+		TransactionID: "txn-expr-3",
+		Amount:        models.MustParseAmount("10"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+		Metadata:      map[string]interface{}{"attempts": "many"},
+	})
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "atm-individual")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "ERROR", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "could not be evaluated")
+
+	// Rules fail closed by default
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+}
+
+func TestValidateRule_InvalidExpression(t *testing.T) {
+	tests := map[string]interface{}{
+		"unknown field":  `amout > 5000`,
+		"syntax error":   `amount >`,
+		"not boolean":    `amount * 2`,
+		"empty":          "",
+		"not a string":   42,
+		"type mismatch":  `currency > 5`,
+		"unknown nested": `counterparty.country == "US"`,
+	}
+
+	for name, expression := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := newExpressionRule("")
+			rule.Config["expression"] = expression
+			assert.ErrorIs(t, ValidateRule(rule, ""), ErrInvalidRule)
+		})
+	}
+}
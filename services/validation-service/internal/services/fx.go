@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gtrs/validation-service/internal/fx"
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// errNoRateProvider is returned when a rule needs conversion but no FX provider is configured
+var errNoRateProvider = errors.New("no FX rate provider configured")
+
+// normalizedAmount is a request amount expressed in the currency a rule compares in
+type normalizedAmount struct {
+	amount   models.Amount
+	currency string
+	rate     *fx.Rate // nil when no conversion was needed
+}
+
+// parseBaseCurrency reads the optional base_currency config of amount-based rules
+func parseBaseCurrency(rule models.ValidationRule) (string, error) {
+	value, present := rule.Config["base_currency"]
+	if !present {
+		return "", nil
+	}
+
+	currency, ok := value.(string)
+	if !ok || len(currency) != 3 || strings.ToUpper(currency) != currency {
+		return "", fmt.Errorf("%w: %s config base_currency must be a 3 letter currency code", ErrInvalidRule, rule.Type)
+	}
+	return currency, nil
+}
+
+// normalizeAmount converts the request amount into baseCurrency, rounded to its minor
+// units. Without a base currency the amount is compared in the request currency.
+func (s *ValidationService) normalizeAmount(ctx context.Context, baseCurrency string, request *models.ValidationRequest) (normalizedAmount, error) {
+	if baseCurrency == "" || baseCurrency == request.Currency {
+		return normalizedAmount{amount: request.Amount, currency: request.Currency}, nil
+	}
+
+	if s.rates == nil {
+		return normalizedAmount{}, errNoRateProvider
+	}
+
+	rate, err := s.rates.Rate(ctx, request.Currency, baseCurrency)
+	if err != nil {
+		return normalizedAmount{}, err
+	}
+
+	converted := models.NewAmount(rate.Convert(request.Amount.Decimal())).Round(baseCurrency)
+	return normalizedAmount{amount: converted, currency: baseCurrency, rate: &rate}, nil
+}
+
+// conversionFailureStatus is FAILED when no rate exists for the currency pair and
+// ERROR when the rate provider itself failed
+func conversionFailureStatus(err error) string {
+	if errors.Is(err, fx.ErrRateNotFound) || errors.Is(err, errNoRateProvider) {
+		return "FAILED"
+	}
+	return "ERROR"
+}
+
+// metadata describes the conversion for the rule result, or nil when none was made
+func (n normalizedAmount) metadata(original models.Amount) map[string]interface{} {
+	if n.rate == nil {
+		return nil
+	}
+
+	fxInfo := map[string]interface{}{
+		"from":             n.rate.From,
+		"to":               n.rate.To,
+		"rate":             n.rate.Value.String(),
+		"source":           n.rate.Source,
+		"original_amount":  original.Format(n.rate.From),
+		"converted_amount": n.amount.Format(n.currency),
+	}
+	if !n.rate.AsOf.IsZero() {
+		fxInfo["as_of"] = n.rate.AsOf
+	}
+
+	return map[string]interface{}{"fx": fxInfo}
+}
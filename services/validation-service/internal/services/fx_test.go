@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/fx"
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateProvider(t *testing.T) fx.RateProvider {
+	t.Helper()
+	provider, err := fx.NewStaticProvider("USD", map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.8"),
+		"JPY": decimal.RequireFromString("150"),
+	}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "test")
+	require.NoError(t, err)
+	return provider
+}
+
+func newUSDLimitRule() models.ValidationRule {
+	return models.ValidationRule{
+		ID:      "amount-limit",
+		Name:    "Amount Limit (USD)",
+		Type:    RuleTypeAmountLimit,
+		Enabled: true,
+		Config: map[string]interface{}{
+			"max_amount":    "10000",
+			"base_currency": "USD",
+		},
+	}
+}
+
+func newFXRequest(amount, currency string) *models.ValidationRequest {
+	return &models.ValidationRequest{
+		TransactionID: "txn-fx",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount(amount),
+		Currency:      currency,
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+	}
+}
+
+func TestValidationService_AmountLimit_BaseCurrency(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithRateProvider(newTestRateProvider(t)))
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{newUSDLimitRule()}))
+
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		status   string
+	}{
+		{"EUR converted within limit", "8000", "EUR", "PASSED"},  // 10000 USD
+		{"EUR converted over limit", "8000.01", "EUR", "FAILED"}, // 10000.0125 -> 10000.01 USD
+		{"JPY converted over limit", "1500150", "JPY", "FAILED"}, // 10001 USD
+		{"base currency needs no rate", "10000", "USD", "PASSED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ValidateTransaction(context.Background(), newFXRequest(tt.amount, tt.currency))
+			require.NoError(t, err)
+
+			ruleResult := findRuleResult(result, "amount-limit")
+			require.NotNil(t, ruleResult)
+			assert.Equal(t, tt.status, ruleResult.Status, ruleResult.Message)
+		})
+	}
+}
+
+func TestValidationService_AmountLimit_RecordsRate(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithRateProvider(newTestRateProvider(t)))
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{newUSDLimitRule()}))
+
+	result, err := service.ValidateTransaction(context.Background(), newFXRequest("400", "EUR"))
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "amount-limit")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "PASSED", ruleResult.Status)
+	assert.Equal(t, "Amount 400.00 EUR (500.00 USD) is within limit of 10000.00 USD", ruleResult.Message)
+
+	fxInfo, ok := ruleResult.Metadata["fx"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "EUR", fxInfo["from"])
+	assert.Equal(t, "USD", fxInfo["to"])
+	assert.Equal(t, "1.25", fxInfo["rate"])
+	assert.Equal(t, "test", fxInfo["source"])
+	assert.Equal(t, "500.00", fxInfo["converted_amount"])
+}
+
+func TestValidationService_AmountLimit_MissingRate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no provider", nil},
+		{"unknown currency", []Option{WithRateProvider(newTestRateProvider(t))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewValidationService(repository.NewMemoryResultRepository(), tt.opts...)
+			require.NoError(t, service.ReplaceRules([]models.ValidationRule{newUSDLimitRule()}))
+
+			result, err := service.ValidateTransaction(context.Background(), newFXRequest("100", "GBP"))
+			require.NoError(t, err)
+
+			ruleResult := findRuleResult(result, "amount-limit")
+			require.NotNil(t, ruleResult)
+			assert.Equal(t, "FAILED", ruleResult.Status)
+			assert.Contains(t, ruleResult.Message, "Cannot convert GBP to USD")
+		})
+	}
+}
+
+func TestValidationService_VelocityRule_BaseCurrency(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithRateProvider(newTestRateProvider(t)))
+	_, err := service.CreateRule(context.Background(), newVelocityRule(map[string]interface{}{
+		"base_currency": "USD",
+		"limits": []interface{}{
+			map[string]interface{}{"window": "1h", "max_amount": "1000"},
+		},
+	}))
+	require.NoError(t, err)
+
+	first := newVelocityRequest("txn-1", "600")
+	result, err := service.ValidateTransaction(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, "PASSED", findRuleResult(result, "counterparty-velocity").Status)
+
+	// 400 EUR is 500 USD, taking the total to 1100 USD
+	second := newVelocityRequest("txn-2", "400")
+	second.Currency = "EUR"
+	result, err = service.ValidateTransaction(context.Background(), second)
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	assert.Equal(t, "FAILED", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "Total amount 1100.00")
+	assert.Contains(t, ruleResult.Metadata, "fx")
+}
+
+func TestValidateRule_BaseCurrency(t *testing.T) {
+	rule := newUSDLimitRule()
+	rule.Config["base_currency"] = "usd"
+	assert.ErrorIs(t, ValidateRule(rule, ""), ErrInvalidRule)
+
+	rule.Config["base_currency"] = 840
+	assert.ErrorIs(t, ValidateRule(rule, ""), ErrInvalidRule)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// ErrIdempotencyConflict is returned when an idempotency key is reused with a different payload
+	ErrIdempotencyConflict = errors.New("idempotency key reused with a different request")
+
+	// ErrIdempotencyInProgress is returned when the first request for a key has not finished yet
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still being processed")
+)
+
+// ValidateTransactionWithKey validates a transaction at most once per idempotency key.
+// An empty key defaults to the transaction ID. Repeating a request with the same key and
+// payload returns the original result with replayed set; a different payload returns
+// ErrIdempotencyConflict. Without an idempotency store every call validates.
+func (s *ValidationService) ValidateTransactionWithKey(ctx context.Context, request *models.ValidationRequest, key string) (result *models.ValidationResult, replayed bool, err error) {
+	ctx, span := s.tracer.Start(ctx, "ValidationService.ValidateTransaction", trace.WithAttributes(
+		attribute.String("transaction.id", request.TransactionID),
+	))
+	defer func() {
+		endValidationSpan(span, result, replayed, err)
+	}()
+
+	if err := request.Validate(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	if s.idempotency == nil {
+		result, err := s.validate(ctx, request)
+		return result, false, err
+	}
+
+	// Explicit keys and transaction IDs live in separate namespaces, per tenant
+	if key == "" {
+		key = "txn:" + request.TransactionID
+	} else {
+		key = "key:" + key
+	}
+	if tenantID := tenant.FromContext(ctx); tenantID != tenant.Default {
+		key = "tenant:" + tenantID + ":" + key
+	}
+
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, reserved, err := s.idempotency.Reserve(ctx, &models.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	if !reserved {
+		if existing.Fingerprint != fingerprint {
+			return nil, false, ErrIdempotencyConflict
+		}
+		if existing.ValidationID == "" {
+			return nil, false, ErrIdempotencyInProgress
+		}
+
+		result, err := s.GetValidationResult(ctx, existing.ValidationID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"transaction_id": request.TransactionID,
+			"validation_id":  result.ID,
+		}).Info("Returning result of repeated validation request")
+		return result, true, nil
+	}
+
+	// Record the outcome even if the caller has gone away
+	storeCtx := context.WithoutCancel(ctx)
+
+	result, err = s.validate(ctx, request)
+	if err != nil {
+		if releaseErr := s.idempotency.Release(storeCtx, key); releaseErr != nil {
+			logrus.WithError(releaseErr).WithField("transaction_id", request.TransactionID).Warn("Failed to release idempotency key")
+		}
+		return nil, false, err
+	}
+
+	if err := s.idempotency.Complete(storeCtx, key, result.ID); err != nil {
+		// The result is stored; a retry within the reservation timeout sees an in-progress key
+		logrus.WithError(err).WithField("validation_id", result.ID).Warn("Failed to record idempotency key")
+	}
+
+	return result, false, nil
+}
+
+// requestFingerprint hashes the request payload. Amounts encode without trailing
+// zeros, so 100 and 100.00 are the same payload.
+func requestFingerprint(request *models.ValidationRequest) (string, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
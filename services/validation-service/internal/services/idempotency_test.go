@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotentService() *ValidationService {
+	return NewValidationService(repository.NewMemoryResultRepository(),
+		WithIdempotency(repository.NewMemoryIdempotencyRepository(time.Hour)))
+}
+
+func newIdempotencyRequest(amount string) *models.ValidationRequest {
+	return &models.ValidationRequest{
+		TransactionID: "txn-idem",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount(amount),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Example Corp", Type: "BUSINESS"},
+	}
+}
+
+func TestValidationService_Idempotency_ReplaysResult(t *testing.T) {
+	service := newIdempotentService()
+
+	first, replayed, err := service.ValidateTransactionWithKey(context.Background(), newIdempotencyRequest("100"), "")
+	require.NoError(t, err)
+	assert.False(t, replayed)
+
+	// Equal amounts with different scale are the same payload
+	second, replayed, err := service.ValidateTransactionWithKey(context.Background(), newIdempotencyRequest("100.00"), "")
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, first.Status, second.Status)
+}
+
+func TestValidationService_Idempotency_Conflict(t *testing.T) {
+	service := newIdempotentService()
+
+	_, err := service.ValidateTransaction(context.Background(), newIdempotencyRequest("100"))
+	require.NoError(t, err)
+
+	_, err = service.ValidateTransaction(context.Background(), newIdempotencyRequest("200"))
+	assert.ErrorIs(t, err, ErrIdempotencyConflict)
+}
+
+func TestValidationService_Idempotency_ExplicitKey(t *testing.T) {
+	service := newIdempotentService()
+
+	first, _, err := service.ValidateTransactionWithKey(context.Background(), newIdempotencyRequest("100"), "retry-1")
+	require.NoError(t, err)
+
+	// An explicit key does not collide with the transaction ID namespace
+	second, replayed, err := service.ValidateTransactionWithKey(context.Background(), newIdempotencyRequest("100"), "")
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	_, _, err = service.ValidateTransactionWithKey(context.Background(), newIdempotencyRequest("300"), "retry-1")
+	assert.ErrorIs(t, err, ErrIdempotencyConflict)
+}
+
+func TestValidationService_Idempotency_InProgress(t *testing.T) {
+	store := repository.NewMemoryIdempotencyRepository(time.Hour)
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithIdempotency(store))
+
+	request := newIdempotencyRequest("100")
+	fingerprint, err := requestFingerprint(request)
+	require.NoError(t, err)
+	_, reserved, err := store.Reserve(context.Background(), &models.IdempotencyRecord{
+		Key:         "txn:" + request.TransactionID,
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	})
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, err = service.ValidateTransaction(context.Background(), request)
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress)
+}
+
+func TestValidationService_Idempotency_ReleasedOnFailure(t *testing.T) {
+	results := &failingResultRepository{
+		MemoryResultRepository: repository.NewMemoryResultRepository(),
+		err:                    errors.New("database unavailable"),
+	}
+	service := NewValidationService(results,
+		WithIdempotency(repository.NewMemoryIdempotencyRepository(time.Hour)))
+
+	_, err := service.ValidateTransaction(context.Background(), newIdempotencyRequest("100"))
+	require.Error(t, err)
+
+	// The failed attempt must not block a retry
+	results.err = nil
+	result, err := service.ValidateTransaction(context.Background(), newIdempotencyRequest("100"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+}
+
+func TestValidationService_Idempotency_DisabledByDefault(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	first, err := service.ValidateTransaction(context.Background(), newIdempotencyRequest("100"))
+	require.NoError(t, err)
+	second, err := service.ValidateTransaction(context.Background(), newIdempotencyRequest("200"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+// failingResultRepository fails Save while err is set
+type failingResultRepository struct {
+	*repository.MemoryResultRepository
+	err error
+}
+
+func (r *failingResultRepository) Save(ctx context.Context, result *models.ValidationResult) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.MemoryResultRepository.Save(ctx, result)
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jobChunkSize is how many items are validated between progress saves. A job
+// interrupted by a restart resumes after its last saved chunk.
+const jobChunkSize = 500
+
+var (
+	// ErrJobQueueFull is returned when no more jobs can be accepted
+	ErrJobQueueFull = errors.New("job queue is full")
+
+	// ErrInvalidCallbackURL is returned when a job callback URL is not an absolute http(s) URL
+	// or names an address callbacks may not be sent to
+	ErrInvalidCallbackURL = errors.New("invalid callback URL")
+
+	// errCallbackAddress is returned when a callback would connect to a forbidden address
+	errCallbackAddress = errors.New("callback address not allowed")
+)
+
+// JobRunnerConfig configures asynchronous job processing
+type JobRunnerConfig struct {
+	Workers          int           // concurrent validations per job
+	QueueSize        int           // jobs accepted but not yet started
+	CallbackAttempts int           // deliveries tried before a callback is marked FAILED
+	CallbackTimeout  time.Duration // per delivery attempt
+	CallbackBackoff  time.Duration // delay before the first retry, doubled for each further retry
+
+	// CallbackAllowPrivate permits callbacks to loopback, private and link-local
+	// addresses, which are refused by default so that callers cannot reach internal
+	// services through the runner
+	CallbackAllowPrivate bool
+}
+
+// JobRunner accepts batch validation jobs and processes them in the background,
+// one job at a time, delivering results to the job's callback URL when done.
+// Callbacks are delivered on goroutines of their own, so a slow or failing callback
+// endpoint does not hold up the jobs queued behind it.
+type JobRunner struct {
+	service *ValidationService
+	jobs    repository.JobRepository
+	config  JobRunnerConfig
+	client  *http.Client
+	slots   chan struct{} // one per queued job, bounding the queue
+	queue   chan string
+	wg      sync.WaitGroup
+}
+
+// NewJobRunner creates a job runner; call Start to begin processing
+func NewJobRunner(service *ValidationService, jobs repository.JobRepository, config JobRunnerConfig) *JobRunner {
+	if config.QueueSize < 1 {
+		config.QueueSize = 1
+	}
+	if config.CallbackAttempts < 1 {
+		config.CallbackAttempts = 1
+	}
+
+	return &JobRunner{
+		service: service,
+		jobs:    jobs,
+		config:  config,
+		client:  newCallbackClient(config),
+		slots:   make(chan struct{}, config.QueueSize),
+		queue:   make(chan string, config.QueueSize),
+	}
+}
+
+// Start resumes jobs left unfinished by a previous run, then processes submitted
+// jobs until ctx is cancelled. A job interrupted by cancellation stays in the store
+// and is resumed by the next Start.
+func (r *JobRunner) Start(ctx context.Context) error {
+	pending, err := r.jobs.ListPending(ctx)
+	if err != nil {
+		return fmt.Errorf("list pending jobs: %w", err)
+	}
+	if len(pending) > 0 {
+		logrus.WithField("jobs", len(pending)).Info("Resuming pending validation jobs")
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		for _, job := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			r.runJob(ctx, job)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-r.queue:
+				<-r.slots
+				job, err := r.jobs.Load(context.Background(), id)
+				if err != nil {
+					logrus.WithError(err).WithField("job_id", id).Error("Failed to load validation job")
+					continue
+				}
+				r.runJob(ctx, job)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Wait blocks until the runner and its callback deliveries have stopped after its
+// context was cancelled
+func (r *JobRunner) Wait() {
+	r.wg.Wait()
+}
+
+// Submit stores a new job for the inputs and queues it. The callback URL is optional.
+func (r *JobRunner) Submit(ctx context.Context, inputs []BatchInput, callbackURL string) (*models.Job, error) {
+	if callbackURL != "" {
+		if err := r.validateCallbackURL(callbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		return nil, ErrJobQueueFull
+	}
+
+	now := time.Now()
+	job := &models.Job{
+		ID:          newID("job"),
+		Status:      models.JobStatusQueued,
+		Total:       len(inputs),
+		CallbackURL: callbackURL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Items:       make([]models.JobItem, len(inputs)),
+		Results:     make([]models.BatchItemResult, 0, len(inputs)),
+	}
+	job.TenantID = tenant.FromContext(ctx)
+	if identity, ok := auth.FromContext(ctx); ok {
+		job.RequestedBy = identity.Caller()
+	}
+	for i, input := range inputs {
+		job.Items[i].Request = input.Request
+		if input.Err != nil {
+			job.Items[i].Error = input.Err.Error()
+		}
+	}
+
+	if err := r.jobs.Save(ctx, job); err != nil {
+		<-r.slots
+		return nil, fmt.Errorf("failed to store job: %w", err)
+	}
+	r.queue <- job.ID
+
+	logrus.WithFields(logrus.Fields{
+		"job_id":    job.ID,
+		"tenant_id": job.TenantID,
+		"total":     job.Total,
+		"callback":  callbackURL != "",
+	}).Info("Validation job queued")
+
+	return job, nil
+}
+
+// GetJob returns the status and progress of a job of the tenant in ctx, without its
+// items and results. It returns repository.ErrNotFound when the tenant has no job
+// with the ID.
+func (r *JobRunner) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := r.jobs.FindByID(ctx, id)
+	if err == nil && job.TenantID != tenant.FromContext(ctx) {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// GetJobResults returns a job of the tenant in ctx like GetJob, with its results so far
+func (r *JobRunner) GetJobResults(ctx context.Context, id string) (*models.Job, error) {
+	job, err := r.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Results, err = r.jobs.FindResults(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve results of job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// runJob validates the job's remaining items chunk by chunk, saving each chunk's
+// results and the job's progress, and then starts delivering its callback
+func (r *JobRunner) runJob(ctx context.Context, job *models.Job) {
+	logger := logrus.WithField("job_id", job.ID)
+
+	if job.Status != models.JobStatusCompleted {
+		job.Status = models.JobStatusRunning
+		for job.Processed < job.Total {
+			if ctx.Err() != nil {
+				r.saveJob(job)
+				logger.WithField("processed", job.Processed).Info("Validation job interrupted")
+				return
+			}
+
+			end := job.Processed + jobChunkSize
+			if end > job.Total {
+				end = job.Total
+			}
+
+			chunk := r.service.ValidateBatch(jobContext(job), jobInputs(job.Items[job.Processed:end]), r.config.Workers)
+			for i := range chunk.Results {
+				chunk.Results[i].Index += job.Processed
+			}
+			if err := r.saveResults(job, chunk.Results); err != nil {
+				return
+			}
+
+			elapsed := job.Summary.ProcessingTime + chunk.Summary.ProcessingTime
+			job.Results = append(job.Results, chunk.Results...)
+			job.Processed = end
+			job.Summary = summarizeBatch(job.Results)
+			job.Summary.ProcessingTime = elapsed
+			if err := r.saveJob(job); err != nil {
+				return
+			}
+		}
+
+		now := time.Now()
+		job.Status = models.JobStatusCompleted
+		job.CompletedAt = &now
+		if job.CallbackURL != "" {
+			job.CallbackStatus = models.CallbackStatusPending
+		}
+		if err := r.saveJob(job); err != nil {
+			return
+		}
+
+		logger.WithFields(logrus.Fields{
+			"total":  job.Summary.Total,
+			"passed": job.Summary.Passed,
+			"review": job.Summary.Review,
+			"failed": job.Summary.Failed,
+			"errors": job.Summary.Errors,
+		}).Info("Validation job completed")
+	}
+
+	if job.CallbackStatus == models.CallbackStatusPending {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.deliverCallback(ctx, job)
+		}()
+	}
+}
+
+// jobContext returns the context a job's items are validated in, carrying the tenant
+// and identity of the caller who submitted it
+func jobContext(job *models.Job) context.Context {
+	ctx := tenant.NewContext(context.Background(), job.TenantID)
+	if job.RequestedBy != nil {
+		ctx = auth.NewContext(ctx, &auth.Identity{Subject: job.RequestedBy.Subject, Method: job.RequestedBy.Method})
+	}
+	return ctx
+}
+
+// deliverCallback POSTs the job results to its callback URL, retrying with
+// exponential backoff. If ctx is cancelled the callback stays pending.
+func (r *JobRunner) deliverCallback(ctx context.Context, job *models.Job) {
+	logger := logrus.WithFields(logrus.Fields{"job_id": job.ID, "callback_url": job.CallbackURL})
+
+	body, err := json.Marshal(models.JobCallback{
+		JobID:   job.ID,
+		Status:  job.Status,
+		Summary: job.Summary,
+		Results: job.Results,
+	})
+	if err != nil {
+		job.CallbackStatus = models.CallbackStatusFailed
+		job.CallbackError = err.Error()
+		r.saveJob(job)
+		return
+	}
+
+	for job.CallbackAttempts < r.config.CallbackAttempts {
+		if job.CallbackAttempts > 0 {
+			delay := r.config.CallbackBackoff << (job.CallbackAttempts - 1)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+
+		err := r.postCallback(ctx, job, body)
+		if ctx.Err() != nil {
+			return
+		}
+
+		job.CallbackAttempts++
+		if err == nil {
+			job.CallbackStatus = models.CallbackStatusDelivered
+			job.CallbackError = ""
+			r.saveJob(job)
+			logger.Info("Validation job callback delivered")
+			return
+		}
+
+		job.CallbackError = err.Error()
+		r.saveJob(job)
+		logger.WithError(err).WithField("attempt", job.CallbackAttempts).Warn("Validation job callback failed")
+	}
+
+	job.CallbackStatus = models.CallbackStatusFailed
+	r.saveJob(job)
+	logger.Error("Validation job callback abandoned")
+}
+
+func (r *JobRunner) postCallback(ctx context.Context, job *models.Job, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Job-ID", job.ID)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// saveJob persists the job, logging failures. Progress writes are not tied to the
+// runner context so that an interrupted job records how far it got.
+func (r *JobRunner) saveJob(job *models.Job) error {
+	job.UpdatedAt = time.Now()
+	if err := r.jobs.Save(context.Background(), job); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("Failed to save validation job")
+		return err
+	}
+	return nil
+}
+
+// saveResults stores a chunk of the job's results, logging failures
+func (r *JobRunner) saveResults(job *models.Job, results []models.BatchItemResult) error {
+	if err := r.jobs.AppendResults(context.Background(), job.ID, results); err != nil {
+		logrus.WithError(err).WithField("job_id", job.ID).Error("Failed to save validation job results")
+		return err
+	}
+	return nil
+}
+
+func jobInputs(items []models.JobItem) []BatchInput {
+	inputs := make([]BatchInput, len(items))
+	for i, item := range items {
+		inputs[i].Request = item.Request
+		if item.Error != "" {
+			inputs[i].Err = errors.New(item.Error)
+		}
+	}
+	return inputs
+}
+
+// validateCallbackURL rejects URLs that are not absolute http(s) URLs and, unless private
+// callbacks are allowed, URLs naming a forbidden IP address. Host names are checked
+// when the callback connects, as they may resolve differently by then.
+func (r *JobRunner) validateCallbackURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCallbackURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: must be an absolute http or https URL", ErrInvalidCallbackURL)
+	}
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && !r.config.CallbackAllowPrivate && forbiddenCallbackIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrInvalidCallbackURL, ip)
+	}
+	return nil
+}
+
+// newCallbackClient returns the HTTP client callbacks are POSTed with. Unless private
+// callbacks are allowed, every connection, including those of redirects, is checked
+// against the resolved address just before it is made.
+func newCallbackClient(config JobRunnerConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: config.CallbackTimeout}
+	if !config.CallbackAllowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || forbiddenCallbackIP(ip) {
+				return fmt.Errorf("%w: %s", errCallbackAddress, host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: config.CallbackTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: config.CallbackTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// forbiddenCallbackIP reports whether ip is a loopback, private, link-local, multicast
+// or unspecified address
+func forbiddenCallbackIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJobRunner(jobs repository.JobRepository, queueSize int) *JobRunner {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	return NewJobRunner(service, jobs, JobRunnerConfig{
+		Workers:          4,
+		QueueSize:        queueSize,
+		CallbackAttempts: 3,
+		CallbackTimeout:  time.Second,
+		CallbackBackoff:  time.Millisecond,
+
+		// The test callback servers listen on loopback
+		CallbackAllowPrivate: true,
+	})
+}
+
+func newJobInputs(n int) []BatchInput {
+	inputs := make([]BatchInput, n)
+	for i := range inputs {
+		inputs[i].Request = newRiskRequest(fmt.Sprintf("%d", 100+i))
+	}
+	return inputs
+}
+
+func waitForJob(t *testing.T, runner *JobRunner, id string, done func(*models.Job) bool) *models.Job {
+	t.Helper()
+
+	var job *models.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = runner.GetJob(context.Background(), id)
+		require.NoError(t, err)
+		return done(job)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Nil(t, job.Results, "GetJob leaves the results unread")
+
+	job, err := runner.GetJobResults(context.Background(), id)
+	require.NoError(t, err)
+	return job
+}
+
+func TestJobRunner_ProcessesJobAndDeliversCallback(t *testing.T) {
+	callbacks := make(chan models.JobCallback, 1)
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var callback models.JobCallback
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&callback))
+		assert.Equal(t, callback.JobID, r.Header.Get("X-Job-ID"))
+		callbacks <- callback
+	}))
+	defer server.Close()
+
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	total := jobChunkSize + 10
+	job, err := runner.Submit(context.Background(), newJobInputs(total), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusQueued, job.Status)
+
+	job = waitForJob(t, runner, job.ID, func(job *models.Job) bool {
+		return job.CallbackStatus == models.CallbackStatusDelivered
+	})
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+	assert.Equal(t, total, job.Processed)
+	assert.Equal(t, total, job.Summary.Passed)
+	assert.Equal(t, 2, job.CallbackAttempts)
+	assert.NotNil(t, job.CompletedAt)
+	require.Len(t, job.Results, total)
+	for i, item := range job.Results {
+		assert.Equal(t, i, item.Index)
+	}
+
+	callback := <-callbacks
+	assert.Equal(t, job.ID, callback.JobID)
+	assert.Equal(t, models.JobStatusCompleted, callback.Status)
+	assert.Len(t, callback.Results, total)
+}
+
+func TestJobRunner_CallbackAbandoned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	job, err := runner.Submit(context.Background(), newJobInputs(1), server.URL)
+	require.NoError(t, err)
+
+	job = waitForJob(t, runner, job.ID, func(job *models.Job) bool {
+		return job.CallbackStatus == models.CallbackStatusFailed
+	})
+	assert.Equal(t, 3, job.CallbackAttempts)
+	assert.Contains(t, job.CallbackError, "status 500")
+}
+
+func TestJobRunner_CallbackDoesNotBlockQueue(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	hanging, err := runner.Submit(context.Background(), newJobInputs(1), server.URL)
+	require.NoError(t, err)
+	next, err := runner.Submit(context.Background(), newJobInputs(1), "")
+	require.NoError(t, err)
+
+	waitForJob(t, runner, next.ID, func(job *models.Job) bool {
+		return job.Status == models.JobStatusCompleted
+	})
+	job, err := runner.GetJob(context.Background(), hanging.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CallbackStatusPending, job.CallbackStatus)
+}
+
+func TestJobRunner_ResumesPendingJobs(t *testing.T) {
+	jobs := repository.NewMemoryJobRepository()
+
+	// Queued by a runner that stopped before processing it
+	stopped := newTestJobRunner(jobs, 10)
+	queued, err := stopped.Submit(context.Background(), newJobInputs(3), "")
+	require.NoError(t, err)
+
+	// Interrupted after its first item
+	inputs := newJobInputs(3)
+	now := time.Now()
+	interrupted := &models.Job{
+		ID:        "job-interrupted",
+		Status:    models.JobStatusRunning,
+		Total:     3,
+		Processed: 1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Items:     make([]models.JobItem, 3),
+	}
+	for i, input := range inputs {
+		interrupted.Items[i].Request = input.Request
+	}
+	interrupted.Items[2] = models.JobItem{Error: "bad json"}
+	require.NoError(t, jobs.Save(context.Background(), interrupted))
+	require.NoError(t, jobs.AppendResults(context.Background(), interrupted.ID, []models.BatchItemResult{{Index: 0, Error: "from previous run"}}))
+
+	runner := newTestJobRunner(jobs, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	done := func(job *models.Job) bool { return job.Status == models.JobStatusCompleted }
+	job := waitForJob(t, runner, queued.ID, done)
+	assert.Equal(t, 3, job.Summary.Passed)
+
+	job = waitForJob(t, runner, interrupted.ID, done)
+	require.Len(t, job.Results, 3)
+	assert.Equal(t, "from previous run", job.Results[0].Error, "processed items are not validated again")
+	assert.Equal(t, 1, job.Results[1].Index)
+	assert.Equal(t, models.ValidationStatusPassed, job.Results[1].Result.Status)
+	assert.Equal(t, 2, job.Results[2].Index)
+	assert.Equal(t, "Invalid request format", job.Results[2].Error)
+	assert.Equal(t, 2, job.Summary.Errors)
+}
+
+func TestJobRunner_SubmitRejected(t *testing.T) {
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 1)
+
+	_, err := runner.Submit(context.Background(), newJobInputs(1), "ftp://example.com/hook")
+	assert.ErrorIs(t, err, ErrInvalidCallbackURL)
+
+	_, err = runner.Submit(context.Background(), newJobInputs(1), "/relative")
+	assert.ErrorIs(t, err, ErrInvalidCallbackURL)
+
+	// Not started, so the single queue slot stays taken
+	_, err = runner.Submit(context.Background(), newJobInputs(1), "")
+	require.NoError(t, err)
+	_, err = runner.Submit(context.Background(), newJobInputs(1), "")
+	assert.ErrorIs(t, err, ErrJobQueueFull)
+}
+
+func TestJobRunner_CallbackPrivateAddress(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	runner := NewJobRunner(NewValidationService(repository.NewMemoryResultRepository()), repository.NewMemoryJobRepository(), JobRunnerConfig{
+		QueueSize:        10,
+		CallbackAttempts: 2,
+		CallbackTimeout:  time.Second,
+		CallbackBackoff:  time.Millisecond,
+	})
+
+	for _, callbackURL := range []string{
+		server.URL,
+		"http://10.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]:8080/hook",
+		"http://0.0.0.0/hook",
+	} {
+		_, err := runner.Submit(context.Background(), newJobInputs(1), callbackURL)
+		assert.ErrorIs(t, err, ErrInvalidCallbackURL, callbackURL)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	// A host name is checked once resolved, when the callback connects
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	job, err := runner.Submit(context.Background(), newJobInputs(1), "http://localhost:"+port+"/hook")
+	require.NoError(t, err)
+
+	job = waitForJob(t, runner, job.ID, func(job *models.Job) bool {
+		return job.CallbackStatus == models.CallbackStatusFailed
+	})
+	assert.Contains(t, job.CallbackError, "callback address not allowed")
+	assert.Zero(t, calls.Load())
+}
+
+func TestJobRunner_GetJob_NotFound(t *testing.T) {
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 1)
+
+	_, err := runner.GetJob(context.Background(), "job-missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestJobRunner_RecordsCaller(t *testing.T) {
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	caller := auth.NewContext(context.Background(), &auth.Identity{Subject: "batch-importer", Method: auth.MethodJWT})
+	job, err := runner.Submit(caller, newJobInputs(2), "")
+	require.NoError(t, err)
+	expected := &models.Caller{Subject: "batch-importer", Method: auth.MethodJWT}
+	assert.Equal(t, expected, job.RequestedBy)
+
+	job = waitForJob(t, runner, job.ID, func(job *models.Job) bool {
+		return job.Status == models.JobStatusCompleted
+	})
+	require.Len(t, job.Results, 2)
+	for _, item := range job.Results {
+		require.NotNil(t, item.Result)
+		assert.Equal(t, expected, item.Result.RequestedBy)
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// MetricsRecorder receives validation and rule outcomes for monitoring
+type MetricsRecorder interface {
+	ObserveValidation(status models.ValidationStatus, duration time.Duration)
+	ObserveRuleResult(ruleID, status string)
+	ObserveRuleDuration(ruleID string, duration time.Duration)
+	SetRuleSetVersion(version int64)
+}
+
+// noopMetrics is the recorder used when metrics are not configured
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveValidation(models.ValidationStatus, time.Duration) {}
+func (noopMetrics) ObserveRuleResult(string, string)                         {}
+func (noopMetrics) ObserveRuleDuration(string, time.Duration)                {}
+func (noopMetrics) SetRuleSetVersion(int64)                                  {}
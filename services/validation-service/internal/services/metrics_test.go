@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics keeps everything reported to it
+type recordingMetrics struct {
+	mu          sync.Mutex
+	validations []models.ValidationStatus
+	rules       map[string]string // rule ID to last status
+	timed       map[string]int    // rule ID to number of durations observed
+	version     int64
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{rules: make(map[string]string), timed: make(map[string]int)}
+}
+
+func (m *recordingMetrics) ObserveValidation(status models.ValidationStatus, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validations = append(m.validations, status)
+}
+
+func (m *recordingMetrics) ObserveRuleResult(ruleID, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules[ruleID] = status
+}
+
+func (m *recordingMetrics) ObserveRuleDuration(ruleID string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timed[ruleID]++
+}
+
+func (m *recordingMetrics) SetRuleSetVersion(version int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.version = version
+}
+
+func TestValidationService_Metrics(t *testing.T) {
+	recorder := newRecordingMetrics()
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithMetrics(recorder))
+	assert.Equal(t, int64(1), recorder.version)
+
+	blocking := newPriorityRule("blocking", 0, "amount > 100")
+	blocking.Blocking = true
+	_, err := service.CreateRule(context.Background(), blocking)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), recorder.version)
+
+	_, err = service.ValidateTransaction(context.Background(), newRiskRequest("5000"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.ValidationStatus{models.ValidationStatusFailed}, recorder.validations)
+	assert.Equal(t, "FAILED", recorder.rules["blocking"])
+	assert.Equal(t, "SKIPPED", recorder.rules["amount-limit"])
+	assert.Equal(t, 1, recorder.timed["blocking"])
+	// Skipped rules were never evaluated
+	assert.Zero(t, recorder.timed["amount-limit"])
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/gtrs/validation-service/internal/models"
+)
+
+// severityWeights are the default risk weights of each rule severity
+var severityWeights = map[string]float64{
+	models.RuleSeverityLow:      10,
+	models.RuleSeverityMedium:   25,
+	models.RuleSeverityHigh:     50,
+	models.RuleSeverityCritical: 100,
+}
+
+// defaultRuleWeight applies to rules without a severity or weight, so that on its
+// own a failing rule still fails the transaction under the default thresholds
+const defaultRuleWeight = 100
+
+// RiskThresholds map an aggregate risk score to a validation outcome: scores at or
+// above Fail are FAILED, scores at or above Review are REVIEW, anything lower PASSED
+type RiskThresholds struct {
+	Review float64
+	Fail   float64
+}
+
+// DefaultRiskThresholds fail on any single critical rule and send medium risk to review
+var DefaultRiskThresholds = RiskThresholds{Review: 50, Fail: 100}
+
+// Validate checks that the thresholds are positive and ordered
+func (t RiskThresholds) Validate() error {
+	if t.Review <= 0 || t.Fail <= 0 {
+		return fmt.Errorf("risk thresholds must be positive")
+	}
+	if t.Review > t.Fail {
+		return fmt.Errorf("review threshold %g must not exceed fail threshold %g", t.Review, t.Fail)
+	}
+	return nil
+}
+
+// Status returns the outcome for a risk score
+func (t RiskThresholds) Status(score float64) models.ValidationStatus {
+	switch {
+	case score >= t.Fail:
+		return models.ValidationStatusFailed
+	case score >= t.Review:
+		return models.ValidationStatusReview
+	default:
+		return models.ValidationStatusPassed
+	}
+}
+
+// ruleWeight returns the risk weight a rule adds to the score when it fails
+func ruleWeight(rule models.ValidationRule) float64 {
+	if rule.Weight > 0 {
+		return rule.Weight
+	}
+	if weight, ok := severityWeights[rule.Severity]; ok {
+		return weight
+	}
+	return defaultRuleWeight
+}
+
+// scoreRuleResult sets the weight a rule result adds to the risk score: the rule's
+// weight when it FAILED, or when it ended in ERROR or TIMEOUT under FAIL_CLOSED.
+// FLAGGED results add no weight; they send the transaction to REVIEW instead. It
+// reports whether the result should make the overall status ERROR.
+func scoreRuleResult(rule models.ValidationRule, result *models.RuleResult) (errored bool) {
+	switch result.Status {
+	case "FAILED":
+		result.Weight = ruleWeight(rule)
+	case "ERROR", "TIMEOUT":
+		switch rule.OnError {
+		case models.RuleOnErrorFailOpen:
+		case models.RuleOnErrorError:
+			return true
+		default:
+			result.Weight = ruleWeight(rule)
+		}
+	}
+	return false
+}
+
+// validateRuleRisk checks a rule's severity, weight and error policy
+func validateRuleRisk(rule models.ValidationRule) error {
+	if _, ok := severityWeights[rule.Severity]; rule.Severity != "" && !ok {
+		return fmt.Errorf("%w: severity must be LOW, MEDIUM, HIGH or CRITICAL", ErrInvalidRule)
+	}
+	if rule.Weight < 0 {
+		return fmt.Errorf("%w: weight must not be negative", ErrInvalidRule)
+	}
+	switch rule.OnError {
+	case "", models.RuleOnErrorFailClosed, models.RuleOnErrorFailOpen, models.RuleOnErrorError:
+	default:
+		return fmt.Errorf("%w: on_error must be FAIL_CLOSED, FAIL_OPEN or ERROR", ErrInvalidRule)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/velocity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newScoredRule returns an EXPRESSION rule that fails when condition holds
+func newScoredRule(id, severity string, weight float64, condition string) models.ValidationRule {
+	return models.ValidationRule{
+		ID:       id,
+		Name:     id,
+		Type:     RuleTypeExpression,
+		Enabled:  true,
+		Severity: severity,
+		Weight:   weight,
+		Config:   map[string]interface{}{"expression": condition},
+	}
+}
+
+func newRiskRequest(amount string) *models.ValidationRequest {
+	return &models.ValidationRequest{
+		TransactionID: "txn-risk",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount(amount),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+	}
+}
+
+func TestRiskThresholds_Status(t *testing.T) {
+	thresholds := RiskThresholds{Review: 40, Fail: 80}
+
+	assert.Equal(t, models.ValidationStatusPassed, thresholds.Status(0))
+	assert.Equal(t, models.ValidationStatusPassed, thresholds.Status(39.9))
+	assert.Equal(t, models.ValidationStatusReview, thresholds.Status(40))
+	assert.Equal(t, models.ValidationStatusReview, thresholds.Status(79))
+	assert.Equal(t, models.ValidationStatusFailed, thresholds.Status(80))
+}
+
+func TestRiskThresholds_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRiskThresholds.Validate())
+	assert.NoError(t, RiskThresholds{Review: 100, Fail: 100}.Validate())
+	assert.Error(t, RiskThresholds{Review: 0, Fail: 100}.Validate())
+	assert.Error(t, RiskThresholds{Review: 120, Fail: 100}.Validate())
+}
+
+func TestValidationService_RiskScore(t *testing.T) {
+	rules := []models.ValidationRule{
+		newScoredRule("over-1000", models.RuleSeverityMedium, 0, "amount > 1000"),
+		newScoredRule("over-5000", models.RuleSeverityMedium, 0, "amount > 5000"),
+		newScoredRule("over-9000", "", 60, "amount > 9000"),
+	}
+
+	tests := []struct {
+		name   string
+		amount string
+		score  float64
+		status models.ValidationStatus
+		code   string
+	}{
+		{"no failures", "500", 0, models.ValidationStatusPassed, ""},
+		{"one medium rule", "2000", 25, models.ValidationStatusPassed, ""},
+		{"two medium rules reach review", "6000", 50, models.ValidationStatusReview, "REVIEW_REQUIRED"},
+		{"weighted rule reaches fail", "10000", 110, models.ValidationStatusFailed, "VALIDATION_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewValidationService(repository.NewMemoryResultRepository())
+			require.NoError(t, service.ReplaceRules(rules))
+
+			result, err := service.ValidateTransaction(context.Background(), newRiskRequest(tt.amount))
+			require.NoError(t, err)
+			assert.Equal(t, tt.score, result.RiskScore)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.code, result.ErrorCode)
+		})
+	}
+}
+
+func TestValidationService_RiskScore_RuleWeights(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{
+		newScoredRule("low", models.RuleSeverityLow, 0, "amount > 0"),
+		newScoredRule("unscored", "", 0, "amount < 0"),
+	}))
+
+	result, err := service.ValidateTransaction(context.Background(), newRiskRequest("100"))
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(10), findRuleResult(result, "low").Weight)
+	assert.Zero(t, findRuleResult(result, "unscored").Weight, "passed rules add no weight")
+}
+
+func TestValidationService_RiskThresholdsOption(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithRiskThresholds(RiskThresholds{Review: 5, Fail: 20}))
+	require.NoError(t, service.ReplaceRules([]models.ValidationRule{
+		newScoredRule("low", models.RuleSeverityLow, 0, "amount > 0"),
+	}))
+
+	result, err := service.ValidateTransaction(context.Background(), newRiskRequest("100"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusReview, result.Status)
+}
+
+func TestValidateRule_InvalidRisk(t *testing.T) {
+	assert.ErrorIs(t, ValidateRule(newScoredRule("bad-severity", "SEVERE", 0, "amount > 0"), ""), ErrInvalidRule)
+	assert.ErrorIs(t, ValidateRule(newScoredRule("bad-weight", "", -1, "amount > 0"), ""), ErrInvalidRule)
+}
+
+// failingVelocityStore stands in for an unavailable velocity backend
+type failingVelocityStore struct{}
+
+func (failingVelocityStore) Record(ctx context.Context, key string, event velocity.Event, windows []time.Duration) ([]velocity.Totals, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newUnavailableRuleService(t *testing.T, onError string) *ValidationService {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithVelocityStore(failingVelocityStore{}))
+
+	rule := newVelocityRule(map[string]interface{}{
+		"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 3}},
+	})
+	rule.OnError = onError
+	_, err := service.CreateRule(context.Background(), rule)
+	require.NoError(t, err)
+	return service
+}
+
+func TestValidationService_RuleErrorPolicy(t *testing.T) {
+	tests := []struct {
+		onError   string
+		status    models.ValidationStatus
+		errorCode string
+		weight    float64
+	}{
+		{"", models.ValidationStatusFailed, "VALIDATION_FAILED", defaultRuleWeight},
+		{models.RuleOnErrorFailClosed, models.ValidationStatusFailed, "VALIDATION_FAILED", defaultRuleWeight},
+		{models.RuleOnErrorFailOpen, models.ValidationStatusPassed, "", 0},
+		{models.RuleOnErrorError, models.ValidationStatusError, "RULE_ERROR", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("on_error="+tt.onError, func(t *testing.T) {
+			service := newUnavailableRuleService(t, tt.onError)
+
+			result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-1", "100"))
+			require.NoError(t, err)
+
+			ruleResult := findRuleResult(result, "counterparty-velocity")
+			require.NotNil(t, ruleResult)
+			assert.Equal(t, "ERROR", ruleResult.Status)
+			assert.Contains(t, ruleResult.Message, "connection refused")
+			assert.Equal(t, tt.weight, ruleResult.Weight)
+
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.errorCode, result.ErrorCode)
+		})
+	}
+}
+
+func TestValidationService_RuleErrorPolicy_FailureWins(t *testing.T) {
+	service := newUnavailableRuleService(t, models.RuleOnErrorError)
+
+	// The amount limit fails outright, so the unevaluated rule cannot change the outcome
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-1", "2000000"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+}
+
+func TestValidateRule_InvalidOnError(t *testing.T) {
+	rule := newScoredRule("bad-policy", "", 0, "amount > 1")
+	rule.OnError = "RETRY"
+	assert.ErrorIs(t, ValidateRule(rule, ""), ErrInvalidRule)
+}
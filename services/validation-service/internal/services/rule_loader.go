@@ -0,0 +1,287 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// tenantRulesDir is the subdirectory of the rules directory holding one directory of
+// rule files per tenant, named by tenant ID
+const tenantRulesDir = "tenants"
+
+// reloadDebounce groups bursts of file events (editors often write several times) into one reload
+const reloadDebounce = 500 * time.Millisecond
+
+// ruleFile is the on-disk format of a rule file
+type ruleFile struct {
+	Rules []models.RuleRequest `json:"rules" yaml:"rules"`
+}
+
+// RuleLoader loads validation rules from a directory of YAML or JSON files
+type RuleLoader struct {
+	dir          string
+	sanctionsDir string
+}
+
+// NewRuleLoader creates a loader for the given rules directory. Sanctions lists named by
+// the rules are loaded from sanctionsDir.
+func NewRuleLoader(dir, sanctionsDir string) *RuleLoader {
+	return &RuleLoader{dir: dir, sanctionsDir: sanctionsDir}
+}
+
+// Load reads every .yaml, .yml and .json file in the directory, in name order,
+// and returns the combined default rule set. Any invalid file fails the whole load.
+func (l *RuleLoader) Load() ([]models.ValidationRule, error) {
+	set, err := loadRuleDir(l.dir, l.sanctionsDir)
+	if err != nil {
+		return nil, err
+	}
+	return set.rules, nil
+}
+
+// LoadTenants reads the rule set of each tenant from tenants/<tenant ID>/ below the
+// rules directory, in the same format as Load. It returns nil without a tenants directory.
+func (l *RuleLoader) LoadTenants() (map[string][]models.ValidationRule, error) {
+	sets, err := l.loadTenantSets()
+	if err != nil || sets == nil {
+		return nil, err
+	}
+
+	tenants := make(map[string][]models.ValidationRule, len(sets))
+	for tenantID, set := range sets {
+		tenants[tenantID] = set.rules
+	}
+	return tenants, nil
+}
+
+// loadTenantSets reads and prepares the rule set of each tenant
+func (l *RuleLoader) loadTenantSets() (map[string]*ruleSet, error) {
+	tenantDirs, err := l.tenantDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var sets map[string]*ruleSet
+	for tenantID, dir := range tenantDirs {
+		if err := tenant.Validate(tenantID); err != nil {
+			return nil, fmt.Errorf("%w: tenant rules directory %s", err, dir)
+		}
+		set, err := loadRuleDir(dir, l.sanctionsDir)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		if sets == nil {
+			sets = make(map[string]*ruleSet)
+		}
+		sets[tenantID] = set
+	}
+
+	return sets, nil
+}
+
+// tenantDirs returns the rules directory of each tenant by tenant ID
+func (l *RuleLoader) tenantDirs() (map[string]string, error) {
+	root := filepath.Join(l.dir, tenantRulesDir)
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read tenant rules directory %s: %w", root, err)
+	}
+
+	dirs := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs[entry.Name()] = filepath.Join(root, entry.Name())
+		}
+	}
+	return dirs, nil
+}
+
+// loadRuleDir reads the combined rule set of one directory and prepares it
+func loadRuleDir(dir, sanctionsDir string) (*ruleSet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read rules directory %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && isRuleFile(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	rules := make([]models.ValidationRule, 0)
+	for _, name := range names {
+		fileRules, err := loadRuleFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("rule file %s: %w", name, err)
+		}
+
+		for _, request := range fileRules {
+			rule := request.ToRule()
+			if rule.Config == nil {
+				rule.Config = make(map[string]interface{})
+			}
+			rule.CreatedAt = now
+			rule.UpdatedAt = now
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("%w: no rules found in %s", ErrInvalidRule, dir)
+	}
+
+	return prepareRuleSet(rules, sanctionsDir)
+}
+
+// Reload loads the default and tenant rule sets and swaps them into the service.
+// Tenants without a rules directory use the default rules. On error the service
+// keeps its current rules.
+func (l *RuleLoader) Reload(service *ValidationService) error {
+	defaults, err := loadRuleDir(l.dir, l.sanctionsDir)
+	if err != nil {
+		return err
+	}
+	tenants, err := l.loadTenantSets()
+	if err != nil {
+		return err
+	}
+	service.installRuleSets(defaults, tenants)
+	return nil
+}
+
+// Watch reloads rules into the service whenever a rule file changes, until ctx is cancelled
+func (l *RuleLoader) Watch(ctx context.Context, service *ValidationService) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create rules watcher: %w", err)
+	}
+
+	if err := watcher.Add(l.dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("watch rules directory %s: %w", l.dir, err)
+	}
+	l.watchTenantDirs(watcher)
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if isRuleFile(event.Name) || l.isTenantDir(event.Name) {
+					debounce = time.After(reloadDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.WithError(err).Warn("Rules watcher error")
+			case <-debounce:
+				debounce = nil
+				l.watchTenantDirs(watcher)
+				if err := l.Reload(service); err != nil {
+					logrus.WithError(err).WithField("rules_dir", l.dir).Error("Rejected rule change, keeping previous rule set")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchTenantDirs adds the tenants directory and each tenant's directory to the
+// watcher; directories already watched are unaffected
+func (l *RuleLoader) watchTenantDirs(watcher *fsnotify.Watcher) {
+	root := filepath.Join(l.dir, tenantRulesDir)
+	if err := watcher.Add(root); err != nil {
+		return
+	}
+
+	dirs, err := l.tenantDirs()
+	if err != nil {
+		logrus.WithError(err).Warn("Cannot watch tenant rules directories")
+		return
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			logrus.WithError(err).WithField("dir", dir).Warn("Cannot watch tenant rules directory")
+		}
+	}
+}
+
+// isTenantDir reports whether path is the tenants directory or directly inside it
+func (l *RuleLoader) isTenantDir(path string) bool {
+	root := filepath.Join(l.dir, tenantRulesDir)
+	return path == root || filepath.Dir(path) == root
+}
+
+func isRuleFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func loadRuleFile(path string) ([]models.RuleRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file ruleFile
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	}
+	if errors.Is(err, io.EOF) {
+		// Empty file
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	for i, request := range file.Rules {
+		if request.Type == "" {
+			return nil, fmt.Errorf("%w: rule %d (%q) is missing type", ErrInvalidRule, i, request.ID)
+		}
+	}
+
+	return file.Rules, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlRules = `
+rules:
+  - id: amount-limit
+    name: Amount Limit Check
+    type: AMOUNT_LIMIT
+    priority: 1
+    config:
+      max_amount: 5000
+`
+
+const jsonRules = `{
+  "rules": [
+    {"id": "currency-check", "name": "Currency Validation", "type": "CURRENCY_CHECK", "priority": 2,
+     "config": {"allowed_currencies": ["EUR"]}}
+  ]
+}`
+
+func writeRuleFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestRuleLoader_Load(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "01-amount.yaml", yamlRules)
+	writeRuleFile(t, dir, "02-currency.json", jsonRules)
+	writeRuleFile(t, dir, "README.txt", "ignored")
+
+	rules, err := NewRuleLoader(dir, "").Load()
+
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "amount-limit", rules[0].ID)
+	assert.True(t, rules[0].Enabled)
+	limit, ok := configFloat(rules[0].Config, "max_amount")
+	assert.True(t, ok)
+	assert.Equal(t, 5000.0, limit)
+	assert.Equal(t, "currency-check", rules[1].ID)
+}
+
+func TestRuleLoader_Load_RejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"bad-syntax.yaml":    "rules: [",
+		"unknown-type.yaml":  "rules:\n  - id: x\n    name: X\n    type: NOPE\n",
+		"unknown-field.json": `{"rules": [{"id": "x", "name": "X", "type": "COUNTERPARTY_CHECK", "colour": "red"}]}`,
+		"bad-config.yaml":    "rules:\n  - id: x\n    name: X\n    type: AMOUNT_LIMIT\n    config:\n      max_amount: -1\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeRuleFile(t, dir, name, content)
+
+			_, err := NewRuleLoader(dir, "").Load()
+			assert.ErrorIs(t, err, ErrInvalidRule)
+		})
+	}
+}
+
+func TestRuleLoader_Load_RejectsDuplicateIDs(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "a.yaml", yamlRules)
+	writeRuleFile(t, dir, "b.yaml", yamlRules)
+
+	_, err := NewRuleLoader(dir, "").Load()
+
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestRuleLoader_Reload_KeepsPreviousRulesOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "rules.yaml", yamlRules)
+
+	loader := NewRuleLoader(dir, "")
+	rules, err := loader.Load()
+	require.NoError(t, err)
+
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, service.ReplaceRules(rules))
+
+	writeRuleFile(t, dir, "rules.yaml", "rules:\n  - id: broken\n    type: AMOUNT_LIMIT\n")
+	assert.Error(t, loader.Reload(service))
+
+	current := service.ListRules(context.Background())
+	require.Len(t, current, 1)
+	assert.Equal(t, "amount-limit", current[0].ID)
+}
+
+func TestRuleLoader_Watch_ReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "rules.yaml", yamlRules)
+
+	loader := NewRuleLoader(dir, "")
+	rules, err := loader.Load()
+	require.NoError(t, err)
+
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, service.ReplaceRules(rules))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, loader.Watch(ctx, service))
+
+	writeRuleFile(t, dir, "currency.json", jsonRules)
+
+	assert.Eventually(t, func() bool {
+		return len(service.ListRules(context.Background())) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestRuleLoader_LoadTenants(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "rules.yaml", yamlRules)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tenants", "retail"), 0o755))
+	writeRuleFile(t, filepath.Join(dir, "tenants", "retail"), "rules.json", jsonRules)
+
+	loader := NewRuleLoader(dir, "")
+	rules, err := loader.Load()
+	require.NoError(t, err)
+	require.Len(t, rules, 1, "tenant directories are not part of the default rules")
+
+	tenants, err := loader.LoadTenants()
+	require.NoError(t, err)
+	require.Len(t, tenants, 1)
+	assert.Equal(t, "currency-check", tenants["retail"][0].ID)
+
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, loader.Reload(service))
+	assert.Equal(t, "currency-check", service.ListRules(tenant.NewContext(context.Background(), "retail"))[0].ID)
+	assert.Equal(t, "amount-limit", service.ListRules(context.Background())[0].ID)
+
+	// An invalid tenant file fails the whole reload
+	writeRuleFile(t, filepath.Join(dir, "tenants", "retail"), "rules.json", "{")
+	assert.Error(t, loader.Reload(service))
+	assert.Equal(t, "currency-check", service.ListRules(tenant.NewContext(context.Background(), "retail"))[0].ID)
+}
@@ -0,0 +1,537 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+
+	"github.com/expr-lang/expr/vm"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrRuleNotFound is returned when a rule ID does not exist
+	ErrRuleNotFound = errors.New("rule not found")
+
+	// ErrRuleExists is returned when creating a rule whose ID is already taken
+	ErrRuleExists = errors.New("rule already exists")
+
+	// ErrInvalidRule is returned when a rule or its config is not valid for its type
+	ErrInvalidRule = errors.New("invalid rule")
+
+	// ErrRulesReadOnly is returned when changing rules that are loaded from files
+	ErrRulesReadOnly = errors.New("rules are loaded from files and cannot be changed through the API")
+)
+
+// Supported rule types
+const (
+	RuleTypeAmountLimit       = "AMOUNT_LIMIT"
+	RuleTypeCurrencyCheck     = "CURRENCY_CHECK"
+	RuleTypeCounterpartyCheck = "COUNTERPARTY_CHECK"
+	RuleTypeExpression        = "EXPRESSION"
+	RuleTypeVelocity          = "VELOCITY"
+	RuleTypeSanctions         = "SANCTIONS_SCREENING"
+)
+
+// ruleSet is an immutable snapshot of the active rules together with state
+// prepared once when the rules are installed
+type ruleSet struct {
+	rules   []models.ValidationRule
+	state   map[string]*ruleState     // by rule ID
+	stages  [][]models.ValidationRule // enabled rules grouped by priority, in evaluation order
+	version int64                     // service-wide, incremented each time a rule set is installed
+}
+
+// ruleState holds per-rule state built when a rule is installed rather than per transaction
+type ruleState struct {
+	program      *vm.Program      // compiled EXPRESSION rule
+	velocity     *velocityConfig  // parsed VELOCITY rule
+	sanctions    *sanctionsConfig // loaded SANCTIONS_SCREENING lists
+	baseCurrency string           // currency amount-based rules compare in; request currency when empty
+	timeout      time.Duration    // rule's own timeout; the service default when zero
+}
+
+// prepareRuleSet validates the rules and builds a snapshot ready for evaluation.
+// Sanctions lists are loaded from sanctionsDir.
+func prepareRuleSet(rules []models.ValidationRule, sanctionsDir string) (*ruleSet, error) {
+	set := &ruleSet{
+		rules:   cloneRules(rules),
+		state:   make(map[string]*ruleState, len(rules)),
+		version: 1,
+	}
+
+	for _, rule := range set.rules {
+		if _, exists := set.state[rule.ID]; exists {
+			return nil, fmt.Errorf("%w: duplicate rule id %q", ErrInvalidRule, rule.ID)
+		}
+
+		state, err := prepareRule(rule, sanctionsDir)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.ID, err)
+		}
+		set.state[rule.ID] = state
+	}
+
+	for _, rule := range sortRules(set.rules) {
+		if !rule.Enabled {
+			continue
+		}
+		last := len(set.stages) - 1
+		if last >= 0 && set.stages[last][0].Priority == rule.Priority {
+			set.stages[last] = append(set.stages[last], rule)
+		} else {
+			set.stages = append(set.stages, []models.ValidationRule{rule})
+		}
+	}
+
+	return set, nil
+}
+
+// ListRules returns the rules of the tenant in ctx ordered by priority, then ID.
+// Tenants without their own rule set see the global default rules.
+func (s *ValidationService) ListRules(ctx context.Context) []models.ValidationRule {
+	return sortRules(s.activeRuleSet(tenant.FromContext(ctx)).rules)
+}
+
+// GetRule returns the rule with the given ID from the rule set of the tenant in ctx
+func (s *ValidationService) GetRule(ctx context.Context, id string) (*models.ValidationRule, error) {
+	for _, rule := range s.activeRuleSet(tenant.FromContext(ctx)).rules {
+		if rule.ID == id {
+			return &rule, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+}
+
+// CreateRule validates and adds a new rule to the rule set of the tenant in ctx. A
+// tenant's first change copies the default rules into a rule set of its own.
+func (s *ValidationService) CreateRule(ctx context.Context, rule models.ValidationRule) (*models.ValidationRule, error) {
+	tenantID := tenant.FromContext(ctx)
+
+	if rule.Config == nil {
+		rule.Config = make(map[string]interface{})
+	}
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	err := s.updateRules(tenantID, func(current []models.ValidationRule) ([]models.ValidationRule, error) {
+		if indexOfRule(current, rule.ID) >= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRuleExists, rule.ID)
+		}
+		return append(cloneRules(current), rule), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"rule_id":   rule.ID,
+		"rule_type": rule.Type,
+		"tenant_id": tenantID,
+	}).Info("Validation rule created")
+
+	return &rule, nil
+}
+
+// UpdateRule validates and replaces an existing rule of the tenant in ctx, keeping its
+// creation time
+func (s *ValidationService) UpdateRule(ctx context.Context, id string, rule models.ValidationRule) (*models.ValidationRule, error) {
+	tenantID := tenant.FromContext(ctx)
+	rule.ID = id
+
+	if rule.Config == nil {
+		rule.Config = make(map[string]interface{})
+	}
+	rule.UpdatedAt = time.Now()
+
+	err := s.updateRules(tenantID, func(current []models.ValidationRule) ([]models.ValidationRule, error) {
+		index := indexOfRule(current, id)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+		}
+
+		rule.CreatedAt = current[index].CreatedAt
+		rules := cloneRules(current)
+		rules[index] = rule
+		return rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"rule_id":   rule.ID,
+		"rule_type": rule.Type,
+		"tenant_id": tenantID,
+	}).Info("Validation rule updated")
+
+	return &rule, nil
+}
+
+// SetRuleEnabled enables or disables an existing rule of the tenant in ctx
+func (s *ValidationService) SetRuleEnabled(ctx context.Context, id string, enabled bool) (*models.ValidationRule, error) {
+	tenantID := tenant.FromContext(ctx)
+
+	var rule models.ValidationRule
+	err := s.updateRules(tenantID, func(current []models.ValidationRule) ([]models.ValidationRule, error) {
+		index := indexOfRule(current, id)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+		}
+
+		rules := cloneRules(current)
+		rules[index].Enabled = enabled
+		rules[index].UpdatedAt = time.Now()
+		rule = rules[index]
+		return rules, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"rule_id":   id,
+		"enabled":   enabled,
+		"tenant_id": tenantID,
+	}).Info("Validation rule toggled")
+
+	return &rule, nil
+}
+
+// DeleteRule removes an existing rule of the tenant in ctx
+func (s *ValidationService) DeleteRule(ctx context.Context, id string) error {
+	tenantID := tenant.FromContext(ctx)
+
+	err := s.updateRules(tenantID, func(current []models.ValidationRule) ([]models.ValidationRule, error) {
+		index := indexOfRule(current, id)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+		}
+
+		rules := make([]models.ValidationRule, 0, len(current)-1)
+		rules = append(rules, current[:index]...)
+		rules = append(rules, current[index+1:]...)
+		return rules, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"rule_id":   id,
+		"tenant_id": tenantID,
+	}).Info("Validation rule deleted")
+
+	return nil
+}
+
+// ReplaceRules validates a complete default rule set and swaps it in atomically.
+// Tenants with their own rule sets keep them. Validations already in progress finish
+// against the previous rule set.
+func (s *ValidationService) ReplaceRules(rules []models.ValidationRule) error {
+	set, err := prepareRuleSet(rules, s.sanctionsDir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.installRuleSet(tenant.Default, set)
+	s.mu.Unlock()
+
+	logrus.WithField("rules_count", len(rules)).Info("Validation rule set replaced")
+
+	return nil
+}
+
+// ReplaceRuleSets validates the default rule set and every tenant's rule set and swaps
+// them all in atomically. Tenants not listed fall back to the default rules. If any
+// rule set is invalid none is installed.
+func (s *ValidationService) ReplaceRuleSets(defaults []models.ValidationRule, tenants map[string][]models.ValidationRule) error {
+	sets := make(map[string]*ruleSet, len(tenants))
+	for tenantID, rules := range tenants {
+		if err := tenant.Validate(tenantID); err != nil || tenantID == tenant.Default {
+			return fmt.Errorf("%w: rule set for invalid tenant %q", ErrInvalidRule, tenantID)
+		}
+		set, err := prepareRuleSet(rules, s.sanctionsDir)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		sets[tenantID] = set
+	}
+	set, err := prepareRuleSet(defaults, s.sanctionsDir)
+	if err != nil {
+		return err
+	}
+
+	s.installRuleSets(set, sets)
+	return nil
+}
+
+// installRuleSets makes prepared default and tenant rule sets the active ones,
+// replacing every rule set installed before
+func (s *ValidationService) installRuleSets(defaults *ruleSet, tenants map[string]*ruleSet) {
+	sets := make(map[string]*ruleSet, len(tenants)+1)
+	for tenantID, set := range tenants {
+		sets[tenantID] = set
+	}
+	sets[tenant.Default] = defaults
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, set := range sets {
+		s.ruleSetVersion++
+		set.version = s.ruleSetVersion
+	}
+	s.ruleSets = sets
+	s.metrics.SetRuleSetVersion(s.ruleSetVersion)
+
+	logrus.WithFields(logrus.Fields{
+		"rules_count":   len(defaults.rules),
+		"tenants_count": len(tenants),
+	}).Info("Validation rule sets replaced")
+}
+
+// updateRules derives new rules for the tenant from its current ones with edit and
+// installs them, unless the rules are read-only. The rules are prepared without holding s.mu, as compiling expressions
+// and loading sanctions lists may take a while and validations must not wait for it;
+// if another writer changed the tenant's rules meanwhile, edit is applied again to
+// theirs.
+func (s *ValidationService) updateRules(tenantID string, edit func(current []models.ValidationRule) ([]models.ValidationRule, error)) error {
+	if s.readOnlyRules {
+		return ErrRulesReadOnly
+	}
+
+	for {
+		base := s.activeRuleSet(tenantID)
+		rules, err := edit(base.rules)
+		if err != nil {
+			return err
+		}
+		set, err := prepareRuleSet(rules, s.sanctionsDir)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		if s.tenantRuleSet(tenantID).version == base.version {
+			s.installRuleSet(tenantID, set)
+			s.mu.Unlock()
+			return nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+// installRuleSet makes a prepared rule set the tenant's active one. Callers must hold
+// s.mu for writing.
+func (s *ValidationService) installRuleSet(tenantID string, set *ruleSet) {
+	sets := make(map[string]*ruleSet, len(s.ruleSets)+1)
+	for id, existing := range s.ruleSets {
+		sets[id] = existing
+	}
+	s.ruleSetVersion++
+	set.version = s.ruleSetVersion
+	sets[tenantID] = set
+
+	s.ruleSets = sets
+	s.metrics.SetRuleSetVersion(set.version)
+}
+
+// ValidateRule checks that a rule has the required fields and that its
+// config is valid for its type. Sanctions lists are loaded from sanctionsDir.
+func ValidateRule(rule models.ValidationRule, sanctionsDir string) error {
+	_, err := prepareRule(rule, sanctionsDir)
+	return err
+}
+
+// prepareRule validates a rule and builds its evaluation state
+func prepareRule(rule models.ValidationRule, sanctionsDir string) (*ruleState, error) {
+	if strings.TrimSpace(rule.ID) == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidRule)
+	}
+	if strings.TrimSpace(rule.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if err := validateRuleRisk(rule); err != nil {
+		return nil, err
+	}
+
+	state := &ruleState{}
+
+	if rule.Timeout != "" {
+		timeout, err := time.ParseDuration(rule.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%w: timeout must be a positive duration such as \"250ms\"", ErrInvalidRule)
+		}
+		state.timeout = timeout
+	}
+
+	switch rule.Type {
+	case RuleTypeAmountLimit:
+		if _, present := rule.Config["max_amount"]; present {
+			limit, ok := configAmount(rule.Config, "max_amount")
+			if !ok || !limit.IsPositive() {
+				return nil, fmt.Errorf("%w: %s config max_amount must be a positive number", ErrInvalidRule, rule.Type)
+			}
+		}
+		baseCurrency, err := parseBaseCurrency(rule)
+		if err != nil {
+			return nil, err
+		}
+		state.baseCurrency = baseCurrency
+	case RuleTypeCurrencyCheck:
+		if _, present := rule.Config["allowed_currencies"]; present {
+			currencies, ok := configStringSlice(rule.Config, "allowed_currencies")
+			if !ok || len(currencies) == 0 {
+				return nil, fmt.Errorf("%w: %s config allowed_currencies must be a non-empty list of strings", ErrInvalidRule, rule.Type)
+			}
+			for _, currency := range currencies {
+				if len(currency) != 3 || strings.ToUpper(currency) != currency {
+					return nil, fmt.Errorf("%w: %s config allowed_currencies contains invalid currency code %q", ErrInvalidRule, rule.Type, currency)
+				}
+			}
+		}
+	case RuleTypeCounterpartyCheck:
+		// No configuration options
+	case RuleTypeExpression:
+		program, err := compileExpressionRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		state.program = program
+	case RuleTypeVelocity:
+		cfg, err := parseVelocityConfig(rule)
+		if err != nil {
+			return nil, err
+		}
+		baseCurrency, err := parseBaseCurrency(rule)
+		if err != nil {
+			return nil, err
+		}
+		state.velocity = cfg
+		state.baseCurrency = baseCurrency
+	case RuleTypeSanctions:
+		cfg, err := parseSanctionsConfig(rule, sanctionsDir)
+		if err != nil {
+			return nil, err
+		}
+		state.sanctions = cfg
+	default:
+		return nil, fmt.Errorf("%w: unsupported rule type %q", ErrInvalidRule, rule.Type)
+	}
+
+	return state, nil
+}
+
+// activeRuleSet returns the tenant's current rule set, or the default rule set for
+// tenants without one. Rule sets are never mutated after being installed, so callers
+// may use the snapshot without locking.
+func (s *ValidationService) activeRuleSet(tenantID string) *ruleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tenantRuleSet(tenantID)
+}
+
+// tenantRuleSet is activeRuleSet for callers already holding s.mu
+func (s *ValidationService) tenantRuleSet(tenantID string) *ruleSet {
+	if set, ok := s.ruleSets[tenantID]; ok {
+		return set
+	}
+	return s.ruleSets[tenant.Default]
+}
+
+func indexOfRule(rules []models.ValidationRule, id string) int {
+	for i, rule := range rules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortRules returns a copy of the rules ordered by priority, then ID
+func sortRules(rules []models.ValidationRule) []models.ValidationRule {
+	sorted := cloneRules(rules)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+func cloneRules(rules []models.ValidationRule) []models.ValidationRule {
+	cloned := make([]models.ValidationRule, len(rules))
+	copy(cloned, rules)
+	return cloned
+}
+
+// configFloat reads a numeric config value regardless of how it was decoded
+func configFloat(config map[string]interface{}, key string) (float64, bool) {
+	switch v := config[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// configAmount reads a monetary config value. Strings are parsed exactly; numbers
+// decoded from JSON or YAML are converted from their shortest decimal representation.
+func configAmount(config map[string]interface{}, key string) (models.Amount, bool) {
+	switch v := config[key].(type) {
+	case string:
+		amount, err := models.ParseAmount(v)
+		return amount, err == nil
+	case float64:
+		return models.NewAmount(decimal.NewFromFloat(v)), true
+	case float32:
+		return models.NewAmount(decimal.NewFromFloat32(v)), true
+	case int:
+		return models.NewAmount(decimal.NewFromInt(int64(v))), true
+	case int64:
+		return models.NewAmount(decimal.NewFromInt(v)), true
+	default:
+		return models.Amount{}, false
+	}
+}
+
+// configStringSlice reads a list of strings from config, accepting both
+// []string literals and []interface{} values decoded from JSON
+func configStringSlice(config map[string]interface{}, key string) ([]string, bool) {
+	switch v := config[key].(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, str)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationService_CreateRule_AppliesToValidation(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	_, err := service.CreateRule(context.Background(), models.ValidationRule{
+		ID:      "eur-only",
+		Name:    "EUR Only",
+		Type:    RuleTypeCurrencyCheck,
+		Enabled: true,
+		Config: map[string]interface{}{
+			// Decoded JSON arrays arrive as []interface{}
+			"allowed_currencies": []interface{}{"EUR"},
+		},
+	})
+	assert.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), &models.ValidationRequest{
+		TransactionID: "txn-rule-1",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("100"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+		Timestamp:     time.Now(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	eurRuleFailed := false
+	for _, rule := range result.Rules {
+		if rule.RuleID == "eur-only" && rule.Status == "FAILED" {
+			eurRuleFailed = true
+		}
+	}
+	assert.True(t, eurRuleFailed, "EUR-only rule should have failed")
+}
+
+func TestValidationService_CreateRule_Duplicate(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	_, err := service.CreateRule(context.Background(), models.ValidationRule{
+		ID:   "amount-limit",
+		Name: "Duplicate",
+		Type: RuleTypeAmountLimit,
+	})
+
+	assert.ErrorIs(t, err, ErrRuleExists)
+}
+
+func TestValidationService_CreateRule_Concurrent(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	// Rules are prepared outside the service lock; concurrent writers must not lose
+	// each other's changes
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := service.CreateRule(context.Background(), models.ValidationRule{
+				ID:      fmt.Sprintf("expression-%d", i),
+				Name:    "Expression",
+				Type:    RuleTypeExpression,
+				Enabled: true,
+				Config:  map[string]interface{}{"expression": fmt.Sprintf("amount > %d", i)},
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, service.ListRules(context.Background()), len(getDefaultValidationRules())+20)
+}
+
+func TestValidationService_UpdateAndDisableRule(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	updated, err := service.UpdateRule(context.Background(), "amount-limit", models.ValidationRule{
+		Name:    "Tighter Amount Limit",
+		Type:    RuleTypeAmountLimit,
+		Enabled: true,
+		Config:  map[string]interface{}{"max_amount": 500},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "amount-limit", updated.ID)
+	assert.False(t, updated.CreatedAt.IsZero())
+
+	request := &models.ValidationRequest{
+		TransactionID: "txn-rule-2",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("1000"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-2", Name: "Test Corp", Type: "BUSINESS"},
+	}
+
+	result, err := service.ValidateTransaction(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	disabled, err := service.SetRuleEnabled(context.Background(), "amount-limit", false)
+	assert.NoError(t, err)
+	assert.False(t, disabled.Enabled)
+
+	result, err = service.ValidateTransaction(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusPassed, result.Status)
+}
+
+func TestValidationService_DeleteRule(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	assert.NoError(t, service.DeleteRule(context.Background(), "counterparty-check"))
+	assert.ErrorIs(t, service.DeleteRule(context.Background(), "counterparty-check"), ErrRuleNotFound)
+
+	_, err := service.GetRule(context.Background(), "counterparty-check")
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.Len(t, service.ListRules(context.Background()), 2)
+}
+
+func TestValidateRule_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.ValidationRule
+	}{
+		{
+			name: "unknown type",
+			rule: models.ValidationRule{ID: "r1", Name: "R1", Type: "NOPE"},
+		},
+		{
+			name: "negative max amount",
+			rule: models.ValidationRule{ID: "r2", Name: "R2", Type: RuleTypeAmountLimit,
+				Config: map[string]interface{}{"max_amount": -5.0}},
+		},
+		{
+			name: "max amount not a number",
+			rule: models.ValidationRule{ID: "r3", Name: "R3", Type: RuleTypeAmountLimit,
+				Config: map[string]interface{}{"max_amount": "lots"}},
+		},
+		{
+			name: "invalid currency code",
+			rule: models.ValidationRule{ID: "r4", Name: "R4", Type: RuleTypeCurrencyCheck,
+				Config: map[string]interface{}{"allowed_currencies": []interface{}{"usd"}}},
+		},
+		{
+			name: "missing id",
+			rule: models.ValidationRule{Name: "R5", Type: RuleTypeCounterpartyCheck},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateRule(tt.rule, ""), ErrInvalidRule)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/namematch"
+	"github.com/gtrs/validation-service/internal/sanctions"
+)
+
+// Actions a screening rule can take on a match
+const (
+	screeningActionFail = "FAIL"
+	screeningActionFlag = "FLAG"
+)
+
+// sanctionsConfig is the parsed config of a SANCTIONS_SCREENING rule
+type sanctionsConfig struct {
+	action  string
+	index   *sanctions.Index
+	matcher *namematch.Matcher
+}
+
+// parseSanctionsConfig reads a SANCTIONS_SCREENING rule config and loads its lists
+// from listDir:
+//
+//	action: FAIL                    # or FLAG to send the transaction to review
+//	lists:
+//	  - name: OFAC SDN
+//	    format: OFAC_SDN_XML         # OFAC_SDN_XML, OFAC_SDN_CSV or EU_CSV
+//	    path: sdn.xml                # relative to listDir
+//	    alt_path: alt.csv            # OFAC_SDN_CSV only, optional
+//	match:                          # optional, exact normalised match by default
+//	  algorithm: jaro_winkler       # exact, jaro_winkler, levenshtein or phonetic
+//	  threshold: 0.9
+func parseSanctionsConfig(rule models.ValidationRule, listDir string) (*sanctionsConfig, error) {
+	cfg := &sanctionsConfig{action: screeningActionFail}
+
+	matcher, err := parseNameMatcher(rule)
+	if err != nil {
+		return nil, err
+	}
+	cfg.matcher = matcher
+
+	if action, present := rule.Config["action"]; present {
+		actionStr, _ := action.(string)
+		actionStr = strings.ToUpper(actionStr)
+		if actionStr != screeningActionFail && actionStr != screeningActionFlag {
+			return nil, fmt.Errorf("%w: %s config action must be FAIL or FLAG", ErrInvalidRule, rule.Type)
+		}
+		cfg.action = actionStr
+	}
+
+	rawLists, ok := rule.Config["lists"].([]interface{})
+	if !ok || len(rawLists) == 0 {
+		return nil, fmt.Errorf("%w: %s config lists must be a non-empty list", ErrInvalidRule, rule.Type)
+	}
+
+	var entries []sanctions.Entry
+	for i, raw := range rawLists {
+		listConfig, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s config lists[%d] must be an object", ErrInvalidRule, rule.Type, i)
+		}
+
+		source := sanctions.Source{}
+		source.Name, _ = listConfig["name"].(string)
+		source.Format, _ = listConfig["format"].(string)
+		source.Path, _ = listConfig["path"].(string)
+		source.AltPath, _ = listConfig["alt_path"].(string)
+
+		if source.Path == "" || source.Format == "" {
+			return nil, fmt.Errorf("%w: %s config lists[%d] needs format and path", ErrInvalidRule, rule.Type, i)
+		}
+		if source.Name == "" {
+			source.Name = source.Format
+		}
+
+		// Paths are checked before any file is touched, so that rules cannot be used to
+		// learn which files exist outside the list directory
+		var err error
+		if source.Path, err = resolveListPath(listDir, source.Path); err != nil {
+			return nil, fmt.Errorf("%w: %s config lists[%d]: %v", ErrInvalidRule, rule.Type, i, err)
+		}
+		if source.AltPath != "" {
+			if source.AltPath, err = resolveListPath(listDir, source.AltPath); err != nil {
+				return nil, fmt.Errorf("%w: %s config lists[%d]: %v", ErrInvalidRule, rule.Type, i, err)
+			}
+		}
+
+		listEntries, err := sanctions.Load(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s config lists[%d]: %v", ErrInvalidRule, rule.Type, i, err)
+		}
+		entries = append(entries, listEntries...)
+	}
+
+	cfg.index = sanctions.NewIndex(entries)
+	return cfg, nil
+}
+
+// resolveListPath returns the path of a list file inside dir. Relative paths are taken
+// from dir; any path that resolves outside dir is rejected.
+func resolveListPath(dir, path string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("no sanctions list directory is configured")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("sanctions list directory: %w", err)
+	}
+
+	resolved := path
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(root, resolved)
+	}
+	resolved = filepath.Clean(resolved)
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("path %q is outside the sanctions list directory", path)
+	}
+	return resolved, nil
+}
+
+// validateSanctions screens the counterparty name against the rule's sanctions lists
+func (s *ValidationService) validateSanctions(rule models.ValidationRule, cfg *sanctionsConfig, request *models.ValidationRequest) models.RuleResult {
+	result := models.RuleResult{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Status:   "PASSED",
+	}
+
+	matches := cfg.index.Screen(request.Counterparty.Name, cfg.matcher)
+	if len(matches) == 0 {
+		result.Message = fmt.Sprintf("Counterparty not found on %d screened list entries", cfg.index.Len())
+		return result
+	}
+
+	best := matches[0]
+	result.Status = "FAILED"
+	if cfg.action == screeningActionFlag {
+		result.Status = "FLAGGED"
+	}
+	result.Message = fmt.Sprintf("Counterparty %q matches %s entry %s (%s) with score %.2f (threshold %.2f)",
+		request.Counterparty.Name, best.Entry.List, best.Entry.UID, best.MatchedName, best.Score, cfg.matcher.Threshold())
+	result.Metadata = map[string]interface{}{
+		"matches":         matches,
+		"match_score":     best.Score,
+		"match_threshold": cfg.matcher.Threshold(),
+		"match_algorithm": cfg.matcher.Algorithm(),
+	}
+
+	return result
+}
+
+// parseNameMatcher reads the optional "match" config shared by counterparty name rules.
+// Without it names must be equal after normalisation.
+func parseNameMatcher(rule models.ValidationRule) (*namematch.Matcher, error) {
+	algorithm := namematch.AlgorithmExact
+	threshold := 1.0
+
+	if raw, present := rule.Config["match"]; present {
+		matchConfig, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s config match must be an object", ErrInvalidRule, rule.Type)
+		}
+
+		if value, present := matchConfig["algorithm"]; present {
+			algorithm, _ = value.(string)
+			if algorithm != namematch.AlgorithmExact {
+				threshold = namematch.DefaultThreshold
+			}
+		}
+		if _, present := matchConfig["threshold"]; present {
+			value, ok := configFloat(matchConfig, "threshold")
+			if !ok {
+				return nil, fmt.Errorf("%w: %s config match.threshold must be a number", ErrInvalidRule, rule.Type)
+			}
+			threshold = value
+		}
+	}
+
+	matcher, err := namematch.NewMatcher(algorithm, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s config match: %v", ErrInvalidRule, rule.Type, err)
+	}
+	return matcher, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/sanctions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testListDir holds the sanctions list files used by the tests
+const testListDir = "../sanctions/testdata"
+
+func newSanctionsRule(action string) models.ValidationRule {
+	return models.ValidationRule{
+		ID:      "sanctions-screening",
+		Name:    "Sanctions Screening",
+		Type:    RuleTypeSanctions,
+		Enabled: true,
+		Config: map[string]interface{}{
+			"action": action,
+			"lists": []interface{}{
+				map[string]interface{}{
+					"name":   "OFAC SDN",
+					"format": sanctions.FormatOFACXML,
+					"path":   "sdn.xml",
+				},
+				map[string]interface{}{
+					"name":   "EU Consolidated",
+					"format": sanctions.FormatEUCSV,
+					"path":   "eu.csv",
+				},
+			},
+		},
+	}
+}
+
+func newScreeningRequest(name string) *models.ValidationRequest {
+	return &models.ValidationRequest{
+		TransactionID: "txn-screen",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("100"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: name, Type: "BUSINESS"},
+	}
+}
+
+func TestValidationService_SanctionsRule_Match(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithSanctionsDir(testListDir))
+	_, err := service.CreateRule(context.Background(), newSanctionsRule("FAIL"))
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), newScreeningRequest("Aero-Caribbean"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	ruleResult := findRuleResult(result, "sanctions-screening")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "FAILED", ruleResult.Status)
+
+	matches, ok := ruleResult.Metadata["matches"].([]sanctions.Match)
+	require.True(t, ok)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "OFAC SDN", matches[0].Entry.List)
+	assert.Equal(t, "36", matches[0].Entry.UID)
+}
+
+func TestValidationService_SanctionsRule_Flag(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithSanctionsDir(testListDir))
+	_, err := service.CreateRule(context.Background(), newSanctionsRule("FLAG"))
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), newScreeningRequest("Iwan Exampl"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusReview, result.Status)
+	assert.Equal(t, "REVIEW_REQUIRED", result.ErrorCode)
+	assert.Contains(t, result.ErrorMessage, "sanctions-screening")
+	assert.Zero(t, result.RiskScore)
+
+	ruleResult := findRuleResult(result, "sanctions-screening")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "FLAGGED", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "EU Consolidated")
+}
+
+func TestValidationService_SanctionsRule_NoMatch(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithSanctionsDir(testListDir))
+	_, err := service.CreateRule(context.Background(), newSanctionsRule("FAIL"))
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), newScreeningRequest("Test Corp"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusPassed, result.Status)
+}
+
+func TestValidateRule_InvalidSanctionsConfig(t *testing.T) {
+	missingFile := newSanctionsRule("FAIL")
+	missingFile.Config["lists"] = []interface{}{
+		map[string]interface{}{"format": sanctions.FormatOFACXML, "path": "does-not-exist.xml"},
+	}
+
+	badAction := newSanctionsRule("BLOCK")
+
+	noLists := newSanctionsRule("FAIL")
+	delete(noLists.Config, "lists")
+
+	badMatcher := newSanctionsRule("FAIL")
+	badMatcher.Config["match"] = map[string]interface{}{"algorithm": "jaro_winkler", "threshold": 2}
+
+	for name, rule := range map[string]models.ValidationRule{
+		"missing file": missingFile,
+		"bad action":   badAction,
+		"no lists":     noLists,
+		"bad matcher":  badMatcher,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateRule(rule, testListDir), ErrInvalidRule)
+		})
+	}
+}
+
+func TestValidateRule_SanctionsListOutsideDir(t *testing.T) {
+	for name, path := range map[string]string{
+		"parent directory": "../load.go",
+		"absolute":         "/etc/passwd",
+		"cleaned escape":   "sub/../../load.go",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := newSanctionsRule("FAIL")
+			rule.Config["lists"] = []interface{}{
+				map[string]interface{}{"format": sanctions.FormatOFACXML, "path": path},
+			}
+			err := ValidateRule(rule, testListDir)
+			assert.ErrorIs(t, err, ErrInvalidRule)
+			assert.Contains(t, err.Error(), "outside the sanctions list directory")
+		})
+	}
+
+	altPath := newSanctionsRule("FAIL")
+	altPath.Config["lists"] = []interface{}{
+		map[string]interface{}{"format": sanctions.FormatOFACCSV, "path": "sdn.csv", "alt_path": "/etc/hosts"},
+	}
+	assert.ErrorIs(t, ValidateRule(altPath, testListDir), ErrInvalidRule)
+
+	// An absolute path inside the directory is accepted
+	abs, err := filepath.Abs(filepath.Join(testListDir, "sdn.xml"))
+	require.NoError(t, err)
+	inside := newSanctionsRule("FAIL")
+	inside.Config["lists"] = []interface{}{
+		map[string]interface{}{"format": sanctions.FormatOFACXML, "path": abs},
+	}
+	assert.NoError(t, ValidateRule(inside, testListDir))
+
+	// Without a configured directory no list can be loaded
+	assert.ErrorIs(t, ValidateRule(newSanctionsRule("FAIL"), ""), ErrInvalidRule)
+}
+
+func TestValidationService_SanctionsRule_FuzzyMatch(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithSanctionsDir(testListDir))
+	rule := newSanctionsRule("FAIL")
+	rule.Config["match"] = map[string]interface{}{
+		"algorithm": "jaro_winkler",
+		"threshold": 0.9,
+	}
+	_, err := service.CreateRule(context.Background(), rule)
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), newScreeningRequest("Ivan Exampel"))
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "sanctions-screening")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "FAILED", ruleResult.Status)
+	assert.Equal(t, 0.9, ruleResult.Metadata["match_threshold"])
+	assert.Equal(t, "jaro_winkler", ruleResult.Metadata["match_algorithm"])
+
+	score, ok := ruleResult.Metadata["match_score"].(float64)
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, score, 0.9)
+	assert.Less(t, score, 1.0)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+)
+
+// ErrInvalidQuery is returned for malformed search parameters, including cursors
+var ErrInvalidQuery = errors.New("invalid search query")
+
+const (
+	// DefaultSearchLimit is the page size when none is requested
+	DefaultSearchLimit = 50
+
+	// MaxSearchLimit is the largest page size accepted
+	MaxSearchLimit = 500
+)
+
+// SearchResults returns one page of the stored validation results of the tenant in ctx.
+// cursor is the NextCursor of the previous page, or empty for the first page, and must
+// come from a search with the same sort order.
+func (s *ValidationService) SearchResults(ctx context.Context, query models.ResultQuery, cursor string) (*models.ResultPage, error) {
+	if err := normalizeQuery(&query); err != nil {
+		return nil, err
+	}
+	query.TenantID = tenant.FromContext(ctx)
+
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if after.SortBy != query.SortBy || after.Descending != query.Descending {
+			return nil, fmt.Errorf("%w: cursor belongs to a different sort order", ErrInvalidQuery)
+		}
+		query.After = after
+	}
+
+	// Fetch one extra result to learn whether there is another page
+	limit := query.Limit
+	query.Limit++
+	results, err := s.results.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search validation results: %w", err)
+	}
+
+	page := &models.ResultPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		last := page.Results[limit-1]
+		page.NextCursor = encodeCursor(&models.ResultCursor{
+			SortBy:      query.SortBy,
+			Descending:  query.Descending,
+			ID:          last.ID,
+			ProcessedAt: last.ProcessedAt,
+			RiskScore:   last.RiskScore,
+			Amount:      last.Amount,
+		})
+	}
+
+	return page, nil
+}
+
+// normalizeQuery applies defaults and rejects contradictory filters
+func normalizeQuery(query *models.ResultQuery) error {
+	switch query.SortBy {
+	case "":
+		query.SortBy = models.ResultSortProcessedAt
+	case models.ResultSortProcessedAt, models.ResultSortRiskScore, models.ResultSortAmount:
+	default:
+		return fmt.Errorf("%w: sort must be processed_at, risk_score or amount", ErrInvalidQuery)
+	}
+
+	switch {
+	case query.Limit == 0:
+		query.Limit = DefaultSearchLimit
+	case query.Limit < 0 || query.Limit > MaxSearchLimit:
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxSearchLimit)
+	}
+
+	filter := query.Filter
+	switch filter.Status {
+	case "", models.ValidationStatusPending, models.ValidationStatusPassed, models.ValidationStatusFailed,
+		models.ValidationStatusReview, models.ValidationStatusError:
+	default:
+		return fmt.Errorf("%w: unknown status %s", ErrInvalidQuery, filter.Status)
+	}
+
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MinAmount.Cmp(*filter.MaxAmount) > 0 {
+		return fmt.Errorf("%w: min_amount is greater than max_amount", ErrInvalidQuery)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+
+	return nil
+}
+
+func encodeCursor(cursor *models.ResultCursor) string {
+	// Marshalling a struct of plain fields cannot fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*models.ResultCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	var decoded models.ResultCursor
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	return &decoded, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationService_SearchResults_Pages(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	for i := 0; i < 5; i++ {
+		_, err := service.ValidateTransaction(context.Background(), &models.ValidationRequest{
+			TransactionID: fmt.Sprintf("txn-page-%d", i),
+			Type:          "PAYMENT",
+			Amount:        models.MustParseAmount(fmt.Sprintf("%d", 100*(i+1))),
+			Currency:      "USD",
+			Counterparty:  models.Counterparty{ID: "cp-page", Name: "Example Corp", Type: "BUSINESS"},
+		})
+		require.NoError(t, err)
+	}
+
+	query := models.ResultQuery{
+		Filter: models.ResultFilter{CounterpartyID: "cp-page"},
+		SortBy: models.ResultSortAmount,
+		Limit:  2,
+	}
+
+	var (
+		seen   []string
+		cursor string
+		pages  int
+	)
+	for {
+		page, err := service.SearchResults(context.Background(), query, cursor)
+		require.NoError(t, err)
+		pages++
+		for _, result := range page.Results {
+			seen = append(seen, result.TransactionID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"txn-page-0", "txn-page-1", "txn-page-2", "txn-page-3", "txn-page-4"}, seen)
+}
+
+func TestValidationService_SearchResults_InvalidQuery(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	min := models.MustParseAmount("500")
+	max := models.MustParseAmount("100")
+
+	otherSort := encodeCursor(&models.ResultCursor{SortBy: models.ResultSortRiskScore, ID: "val-1"})
+
+	tests := map[string]struct {
+		query  models.ResultQuery
+		cursor string
+	}{
+		"unknown sort":       {query: models.ResultQuery{SortBy: "name"}},
+		"limit too large":    {query: models.ResultQuery{Limit: MaxSearchLimit + 1}},
+		"unknown status":     {query: models.ResultQuery{Filter: models.ResultFilter{Status: "DONE"}}},
+		"inverted amounts":   {query: models.ResultQuery{Filter: models.ResultFilter{MinAmount: &min, MaxAmount: &max}}},
+		"malformed cursor":   {cursor: "not a cursor"},
+		"cursor sort change": {cursor: otherSort},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.SearchResults(context.Background(), tt.query, tt.cursor)
+			assert.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTenantRules(maxAmount string) []models.ValidationRule {
+	return []models.ValidationRule{{
+		ID:       "amount-limit",
+		Name:     "Amount Limit Check",
+		Type:     RuleTypeAmountLimit,
+		Enabled:  true,
+		Priority: 1,
+		Config:   map[string]interface{}{"max_amount": maxAmount},
+	}}
+}
+
+func TestValidationService_TenantRuleSets(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	require.NoError(t, service.ReplaceRuleSets(getDefaultValidationRules(), map[string][]models.ValidationRule{
+		"retail": newTenantRules("1000"),
+	}))
+
+	retail := tenant.NewContext(context.Background(), "retail")
+	corporate := tenant.NewContext(context.Background(), "corporate")
+
+	result, err := service.ValidateTransaction(retail, newRiskRequest("5000"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+	assert.Equal(t, "retail", result.TenantID)
+	assert.Len(t, result.Rules, 1)
+
+	// Tenants without their own rule set use the default rules
+	result, err = service.ValidateTransaction(corporate, newRiskRequest("5000"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusPassed, result.Status)
+	assert.Equal(t, "corporate", result.TenantID)
+	assert.Len(t, result.Rules, 3)
+
+	assert.Len(t, service.ListRules(retail), 1)
+	assert.Len(t, service.ListRules(corporate), 3)
+
+	// Invalid tenant rule sets are rejected as a whole
+	err = service.ReplaceRuleSets(getDefaultValidationRules(), map[string][]models.ValidationRule{
+		"retail": newTenantRules("-1"),
+	})
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	assert.Len(t, service.ListRules(retail), 1)
+}
+
+func TestValidationService_TenantRuleChangesCopyDefaults(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	retail := tenant.NewContext(context.Background(), "retail")
+
+	_, err := service.UpdateRule(retail, "amount-limit", newTenantRules("1000")[0])
+	require.NoError(t, err)
+
+	rule, err := service.GetRule(retail, "amount-limit")
+	require.NoError(t, err)
+	assert.Equal(t, "1000", rule.Config["max_amount"])
+	assert.Len(t, service.ListRules(retail), 3, "the tenant's rule set starts as a copy of the defaults")
+
+	rule, err = service.GetRule(context.Background(), "amount-limit")
+	require.NoError(t, err)
+	assert.Equal(t, "1000000", rule.Config["max_amount"], "the default rules are unchanged")
+
+	require.NoError(t, service.DeleteRule(retail, "currency-check"))
+	assert.Len(t, service.ListRules(retail), 2)
+	assert.Len(t, service.ListRules(context.Background()), 3)
+}
+
+func TestValidationService_TenantResultIsolation(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository(),
+		WithIdempotency(repository.NewMemoryIdempotencyRepository(time.Hour)))
+	retail := tenant.NewContext(context.Background(), "retail")
+	corporate := tenant.NewContext(context.Background(), "corporate")
+
+	result, err := service.ValidateTransaction(retail, newRiskRequest("100"))
+	require.NoError(t, err)
+
+	_, err = service.GetValidationResult(corporate, result.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = service.GetValidationResult(context.Background(), result.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	found, err := service.GetValidationResult(retail, result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.ID, found.ID)
+
+	// The same transaction ID in another tenant is a different transaction
+	other, replayed, err := service.ValidateTransactionWithKey(corporate, newRiskRequest("100"), "")
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, result.ID, other.ID)
+
+	page, err := service.SearchResults(corporate, models.ResultQuery{}, "")
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, other.ID, page.Results[0].ID)
+}
+
+func TestJobRunner_TenantIsolation(t *testing.T) {
+	runner := newTestJobRunner(repository.NewMemoryJobRepository(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); runner.Wait() }()
+	require.NoError(t, runner.Start(ctx))
+
+	retail := tenant.NewContext(context.Background(), "retail")
+	job, err := runner.Submit(retail, newJobInputs(1), "")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		job, err = runner.GetJob(retail, job.ID)
+		require.NoError(t, err)
+		return job.Status == models.JobStatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "retail", job.TenantID)
+	job, err = runner.GetJobResults(retail, job.ID)
+	require.NoError(t, err)
+	require.Len(t, job.Results, 1)
+	assert.Equal(t, "retail", job.Results[0].Result.TenantID)
+
+	_, err = runner.GetJob(tenant.NewContext(context.Background(), "corporate"), job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = runner.GetJob(context.Background(), job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = runner.GetJobResults(tenant.NewContext(context.Background(), "corporate"), job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
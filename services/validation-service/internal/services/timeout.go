@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Timeouts bounds validation time. Zero means no limit.
+type Timeouts struct {
+	Request time.Duration // whole validation, across all rules
+	Rule    time.Duration // each rule, unless the rule sets its own timeout
+}
+
+// cancelled reports whether the caller cancelled ctx. An expired deadline is not a
+// cancellation: rules that run out of time are recorded as TIMEOUT instead.
+func cancelled(ctx context.Context) error {
+	if err := ctx.Err(); errors.Is(err, context.Canceled) {
+		return fmt.Errorf("validation cancelled: %w", err)
+	}
+	return nil
+}
+
+// evaluateRule applies a rule within its timeout and the request deadline. A rule that
+// does not finish in time is recorded as TIMEOUT; it keeps running in the background
+// until it next checks its context.
+func (s *ValidationService) evaluateRule(ctx context.Context, rule models.ValidationRule, state *ruleState, request *models.ValidationRequest) models.RuleResult {
+	start := time.Now()
+	defer func() {
+		s.metrics.ObserveRuleDuration(rule.ID, time.Since(start))
+	}()
+
+	timeout := s.timeouts.Rule
+	if state.timeout > 0 {
+		timeout = state.timeout
+	}
+
+	ruleCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ruleCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Without any deadline there is nothing to wait for
+	if ruleCtx.Done() == nil {
+		return s.applyRule(ruleCtx, rule, state, request)
+	}
+
+	if ruleCtx.Err() != nil {
+		return timeoutResult(ctx, rule, timeout)
+	}
+
+	done := make(chan models.RuleResult, 1)
+	go func() {
+		done <- s.applyRule(ruleCtx, rule, state, request)
+	}()
+
+	select {
+	case result := <-done:
+		// A dependency that gave up because the deadline passed is a timeout, not an error
+		if result.Status == "ERROR" && ruleCtx.Err() != nil {
+			return timeoutResult(ctx, rule, timeout)
+		}
+		return result
+	case <-ruleCtx.Done():
+		return timeoutResult(ctx, rule, timeout)
+	}
+}
+
+// timeoutResult records a rule that ran out of time, saying whose deadline expired
+func timeoutResult(ctx context.Context, rule models.ValidationRule, timeout time.Duration) models.RuleResult {
+	result := models.RuleResult{
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		Status:      "TIMEOUT",
+		Message:     fmt.Sprintf("Rule did not complete within %s", timeout),
+		ProcessedAt: time.Now(),
+	}
+	if ctx.Err() != nil {
+		result.Message = "Validation deadline exceeded before rule completed"
+	}
+
+	// The rule's own span ends only when the rule returns, which may be much later
+	trace.SpanFromContext(ctx).AddEvent("Rule timed out", trace.WithAttributes(
+		attribute.String("rule.id", rule.ID),
+		attribute.String("message", result.Message),
+	))
+	return result
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/velocity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingVelocityStore never answers before its context is done
+type blockingVelocityStore struct{}
+
+func (blockingVelocityStore) Record(ctx context.Context, key string, event velocity.Event, windows []time.Duration) ([]velocity.Totals, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func newSlowRuleService(t *testing.T, timeouts Timeouts, ruleTimeout string) *ValidationService {
+	service := NewValidationService(repository.NewMemoryResultRepository(),
+		WithVelocityStore(blockingVelocityStore{}),
+		WithTimeouts(timeouts))
+
+	rule := newVelocityRule(map[string]interface{}{
+		"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 3}},
+	})
+	rule.Timeout = ruleTimeout
+	_, err := service.CreateRule(context.Background(), rule)
+	require.NoError(t, err)
+	return service
+}
+
+func TestValidationService_RuleTimeout(t *testing.T) {
+	service := newSlowRuleService(t, Timeouts{Rule: 20 * time.Millisecond}, "")
+
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-slow", "100"))
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "TIMEOUT", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "20ms")
+
+	// A timed out rule counts against the transaction rather than passing silently
+	assert.Equal(t, float64(defaultRuleWeight), result.RiskScore)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	// Other rules are unaffected
+	amountResult := findRuleResult(result, "amount-limit")
+	require.NotNil(t, amountResult)
+	assert.Equal(t, "PASSED", amountResult.Status)
+}
+
+func TestValidationService_RuleTimeout_RuleOverride(t *testing.T) {
+	service := newSlowRuleService(t, Timeouts{Rule: time.Hour}, "10ms")
+
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-slow", "100"))
+	require.NoError(t, err)
+
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "TIMEOUT", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "10ms")
+}
+
+func TestValidationService_RequestDeadline(t *testing.T) {
+	service := newSlowRuleService(t, Timeouts{Request: 20 * time.Millisecond}, "")
+
+	start := time.Now()
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-slow", "100"))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "TIMEOUT", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "deadline exceeded")
+
+	// The result is stored even though the deadline had passed
+	stored, err := service.GetValidationResult(context.Background(), result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.Status, stored.Status)
+}
+
+func TestValidationService_Cancelled(t *testing.T) {
+	service := newSlowRuleService(t, Timeouts{}, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := service.ValidateTransaction(ctx, newVelocityRequest("txn-slow", "100"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestValidateRule_InvalidTimeout(t *testing.T) {
+	for _, timeout := range []string{"soon", "-1s", "0s"} {
+		rule := newVelocityRule(map[string]interface{}{
+			"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 3}},
+		})
+		rule.Timeout = timeout
+		assert.ErrorIs(t, ValidateRule(rule, ""), ErrInvalidRule, timeout)
+	}
+}
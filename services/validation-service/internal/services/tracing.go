@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github.com/gtrs/validation-service/internal/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by the validation service
+const tracerName = "github.com/gtrs/validation-service/internal/services"
+
+// endValidationSpan records the outcome of a validation on its span and ends it
+func endValidationSpan(span trace.Span, result *models.ValidationResult, replayed bool, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("validation.id", result.ID),
+		attribute.String("validation.status", string(result.Status)),
+		attribute.Float64("validation.risk_score", result.RiskScore),
+		attribute.Bool("validation.replayed", replayed),
+	)
+}
+
+// endRuleSpan records a rule result on its span and ends it. Only rules that could not
+// be evaluated mark the span as an error; a failed rule is a normal outcome.
+func endRuleSpan(span trace.Span, result models.RuleResult) {
+	defer span.End()
+
+	span.SetAttributes(attribute.String("rule.status", result.Status))
+	if result.Status == "ERROR" {
+		span.SetStatus(codes.Error, result.Message)
+	}
+}
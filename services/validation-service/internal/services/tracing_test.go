@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestValidationService_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	service := NewValidationService(repository.NewMemoryResultRepository(), WithTracerProvider(provider))
+	_, err := service.CreateRule(context.Background(), newExpressionRule(`metadata.attempts > 3`))
+	require.NoError(t, err)
+
+	// Continue a trace started by the caller, as propagated in a traceparent header
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+
+	result, err := service.ValidateTransaction(ctx, &models.ValidationRequest{
+		TransactionID: "txn-traced",
+		Amount:        models.MustParseAmount("10"),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-1", Name: "Test Corp", Type: "BUSINESS"},
+		Metadata:      map[string]interface{}{"attempts": "many"},
+	})
+	require.NoError(t, err)
+
+	var validationSpan sdktrace.ReadOnlySpan
+	ruleSpans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "ValidationService.ValidateTransaction":
+			validationSpan = span
+		case "ValidationService.applyRule":
+			ruleSpans[spanAttribute(span, "rule.id").AsString()] = span
+		}
+	}
+
+	require.NotNil(t, validationSpan)
+	assert.Equal(t, traceID, validationSpan.SpanContext().TraceID())
+	assert.Equal(t, spanID, validationSpan.Parent().SpanID())
+	assert.Equal(t, "txn-traced", spanAttribute(validationSpan, "transaction.id").AsString())
+	assert.Equal(t, result.ID, spanAttribute(validationSpan, "validation.id").AsString())
+	assert.Equal(t, string(result.Status), spanAttribute(validationSpan, "validation.status").AsString())
+
+	require.Len(t, ruleSpans, len(result.Rules))
+	for _, span := range ruleSpans {
+		assert.Equal(t, validationSpan.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	// Only a rule that could not be evaluated marks its span as an error
+	assert.Equal(t, "ERROR", spanAttribute(ruleSpans["atm-individual"], "rule.status").AsString())
+	assert.Equal(t, codes.Error, ruleSpans["atm-individual"].Status().Code)
+	assert.Equal(t, codes.Unset, ruleSpans["amount-limit"].Status().Code)
+}
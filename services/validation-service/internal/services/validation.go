@@ -1,111 +1,342 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gtrs/validation-service/internal/auth"
+	"github.com/gtrs/validation-service/internal/fx"
 	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/tenant"
+	"github.com/gtrs/validation-service/internal/velocity"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrInvalidRequest is returned when a validation request fails checks beyond struct binding
+var ErrInvalidRequest = errors.New("invalid validation request")
+
+// lastIDTimestamp is the timestamp part of the most recently issued ID
+var lastIDTimestamp atomic.Int64
+
 // ValidationService handles transaction validation logic
 type ValidationService struct {
-	rules []models.ValidationRule
+	mu             sync.RWMutex
+	ruleSets       map[string]*ruleSet // by tenant; tenant.Default holds the global rules
+	ruleSetVersion int64
+	results        repository.ValidationResultRepository
+	velocityStore  velocity.Store
+	rates          fx.RateProvider
+	thresholds     RiskThresholds
+	idempotency    repository.IdempotencyRepository
+	timeouts       Timeouts
+	metrics        MetricsRecorder
+	tracer         trace.Tracer
+	sanctionsDir   string // SANCTIONS_SCREENING list files must lie in this directory
+	readOnlyRules  bool   // rule sets come from files; CreateRule and friends are refused
+}
+
+// Option configures optional ValidationService dependencies
+type Option func(*ValidationService)
+
+// WithReadOnlyRules refuses rule changes through CreateRule, UpdateRule, SetRuleEnabled
+// and DeleteRule with ErrRulesReadOnly, for rule sets loaded from files, which would
+// silently replace such changes on their next reload
+func WithReadOnlyRules() Option {
+	return func(s *ValidationService) {
+		s.readOnlyRules = true
+	}
+}
+
+// WithVelocityStore sets the store used by VELOCITY rules (in-memory by default)
+func WithVelocityStore(store velocity.Store) Option {
+	return func(s *ValidationService) {
+		s.velocityStore = store
+	}
+}
+
+// WithRateProvider sets the FX rate provider used by rules with a base_currency
+func WithRateProvider(provider fx.RateProvider) Option {
+	return func(s *ValidationService) {
+		s.rates = provider
+	}
+}
+
+// WithRiskThresholds sets the risk score thresholds for REVIEW and FAILED outcomes
+func WithRiskThresholds(thresholds RiskThresholds) Option {
+	return func(s *ValidationService) {
+		s.thresholds = thresholds
+	}
+}
+
+// WithIdempotency makes repeated requests for the same transaction return the original
+// result instead of validating again (disabled by default)
+func WithIdempotency(store repository.IdempotencyRepository) Option {
+	return func(s *ValidationService) {
+		s.idempotency = store
+	}
+}
+
+// WithTimeouts bounds how long a validation and each of its rules may run (unbounded by default)
+func WithTimeouts(timeouts Timeouts) Option {
+	return func(s *ValidationService) {
+		s.timeouts = timeouts
+	}
+}
+
+// WithMetrics reports validation and rule outcomes to the recorder
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(s *ValidationService) {
+		s.metrics = recorder
+	}
 }
 
-// NewValidationService creates a new validation service
-func NewValidationService() *ValidationService {
+// WithSanctionsDir sets the directory that SANCTIONS_SCREENING rules load list files
+// from. Without it such rules are rejected.
+func WithSanctionsDir(dir string) Option {
+	return func(s *ValidationService) {
+		s.sanctionsDir = dir
+	}
+}
+
+// WithTracerProvider sets the provider of validation and rule spans (the global provider by default)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *ValidationService) {
+		s.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// NewValidationService creates a new validation service that stores results in the given repository.
+// It starts with the built-in default rules; use ReplaceRules to install a different rule set.
+func NewValidationService(results repository.ValidationResultRepository, opts ...Option) *ValidationService {
+	// The built-in rules do not screen sanctions lists, so they need no list directory
+	set, err := prepareRuleSet(getDefaultValidationRules(), "")
+	if err != nil {
+		panic(fmt.Sprintf("invalid default validation rules: %v", err))
+	}
+
 	service := &ValidationService{
-		rules: getDefaultValidationRules(),
+		ruleSets:       map[string]*ruleSet{tenant.Default: set},
+		ruleSetVersion: set.version,
+		results:        results,
+		velocityStore:  velocity.NewMemoryStore(),
+		thresholds:     DefaultRiskThresholds,
+		metrics:        noopMetrics{},
+		tracer:         otel.Tracer(tracerName),
 	}
 
-	logrus.WithField("rules_count", len(service.rules)).Info("Validation service initialized")
+	for _, opt := range opts {
+		opt(service)
+	}
+	service.metrics.SetRuleSetVersion(set.version)
+
+	logrus.WithField("rules_count", len(set.rules)).Info("Validation service initialized")
 	return service
 }
 
-// ValidateTransaction validates a transaction against all enabled rules
-func (s *ValidationService) ValidateTransaction(request *models.ValidationRequest) (*models.ValidationResult, error) {
+// ValidateTransaction validates a transaction against all enabled rules.
+// With idempotency enabled the transaction ID is the idempotency key.
+// If ctx is cancelled the validation stops and no result is stored.
+func (s *ValidationService) ValidateTransaction(ctx context.Context, request *models.ValidationRequest) (*models.ValidationResult, error) {
+	result, _, err := s.ValidateTransactionWithKey(ctx, request, "")
+	return result, err
+}
+
+// validate runs the rules against an already checked request and stores the result
+func (s *ValidationService) validate(ctx context.Context, request *models.ValidationRequest) (*models.ValidationResult, error) {
 	startTime := time.Now()
 
+	// The result is stored even if the deadline passed while the rules ran
+	storeCtx := context.WithoutCancel(ctx)
+	if s.timeouts.Request > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeouts.Request)
+		defer cancel()
+	}
+
+	tenantID := tenant.FromContext(ctx)
 	result := &models.ValidationResult{
-		ID:            fmt.Sprintf("val-%d", time.Now().UnixNano()),
-		TransactionID: request.TransactionID,
-		Status:        models.ValidationStatusPending,
-		Rules:         make([]models.RuleResult, 0),
-		ProcessedAt:   startTime,
-		Metadata:      make(map[string]interface{}),
+		ID:             newID("val"),
+		TenantID:       tenantID,
+		TransactionID:  request.TransactionID,
+		CounterpartyID: request.Counterparty.ID,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Status:         models.ValidationStatusPending,
+		Rules:          make([]models.RuleResult, 0),
+		ProcessedAt:    startTime,
+		Metadata:       make(map[string]interface{}),
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		result.RequestedBy = identity.Caller()
 	}
 
 	logrus.WithFields(logrus.Fields{
 		"transaction_id": request.TransactionID,
 		"validation_id":  result.ID,
-		"amount":         request.Amount,
+		"tenant_id":      tenantID,
+		"amount":         request.Amount.String(),
 		"currency":       request.Currency,
 	}).Info("Starting transaction validation")
 
-	// Apply validation rules
-	overallStatus := models.ValidationStatusPassed
-	for _, rule := range s.rules {
-		if !rule.Enabled {
-			continue
+	// Apply validation rules in priority order, adding the weight of each failed rule to
+	// the risk score. Rules that end in ERROR or TIMEOUT follow their error policy. Once a
+	// blocking rule fails, rules of lower priority are skipped.
+	var erroredRules, flaggedRules []string
+	var blockedBy string
+	set := s.activeRuleSet(tenantID)
+	for _, stage := range set.stages {
+		if err := cancelled(ctx); err != nil {
+			return nil, err
 		}
 
-		ruleResult := s.applyRule(rule, request)
-		result.Rules = append(result.Rules, ruleResult)
+		var stageResults []models.RuleResult
+		if blockedBy != "" {
+			stageResults = skippedResults(stage, blockedBy)
+		} else {
+			stageResults = s.evaluateStage(ctx, stage, set, request)
+		}
 
-		if ruleResult.Status == "FAILED" {
-			overallStatus = models.ValidationStatusFailed
+		for i, rule := range stage {
+			ruleResult := stageResults[i]
+			if scoreRuleResult(rule, &ruleResult) {
+				erroredRules = append(erroredRules, rule.ID)
+			}
+			if ruleResult.Status == "FLAGGED" {
+				flaggedRules = append(flaggedRules, rule.ID)
+			}
+			if rule.Blocking && ruleResult.Weight > 0 && blockedBy == "" {
+				blockedBy = rule.ID
+				logrus.WithFields(logrus.Fields{
+					"transaction_id": request.TransactionID,
+					"validation_id":  result.ID,
+					"rule_id":        rule.ID,
+				}).Info("Blocking rule failed, skipping lower priority rules")
+				trace.SpanFromContext(ctx).AddEvent("Blocking rule failed", trace.WithAttributes(attribute.String("rule.id", rule.ID)))
+			}
+			s.metrics.ObserveRuleResult(rule.ID, ruleResult.Status)
+			result.RiskScore += ruleResult.Weight
+			result.Rules = append(result.Rules, ruleResult)
 		}
 	}
+	if err := cancelled(ctx); err != nil {
+		return nil, err
+	}
 
+	// A definite failure stands; otherwise an unevaluated ERROR-policy rule leaves the outcome undecided
+	overallStatus := s.thresholds.Status(result.RiskScore)
+	if len(flaggedRules) > 0 && overallStatus == models.ValidationStatusPassed {
+		// A flagged rule asks for a human decision whatever the score
+		overallStatus = models.ValidationStatusReview
+	}
+	if len(erroredRules) > 0 && overallStatus != models.ValidationStatusFailed {
+		overallStatus = models.ValidationStatusError
+	}
 	result.Status = overallStatus
 	result.ProcessingTime = time.Since(startTime)
 
-	// Set error details if validation failed
-	if overallStatus == models.ValidationStatusFailed {
+	// Set error details if validation failed, needs review or could not complete
+	switch overallStatus {
+	case models.ValidationStatusFailed:
 		result.ErrorCode = "VALIDATION_FAILED"
 		result.ErrorMessage = "One or more validation rules failed"
+	case models.ValidationStatusReview:
+		result.ErrorCode = "REVIEW_REQUIRED"
+		result.ErrorMessage = fmt.Sprintf("Risk score %g requires manual review", result.RiskScore)
+		if len(flaggedRules) > 0 {
+			result.ErrorMessage = fmt.Sprintf("Flagged rules require manual review: %s", strings.Join(flaggedRules, ", "))
+		}
+	case models.ValidationStatusError:
+		result.ErrorCode = "RULE_ERROR"
+		result.ErrorMessage = fmt.Sprintf("Rules could not be evaluated: %s", strings.Join(erroredRules, ", "))
 	}
 
+	if err := s.results.Save(storeCtx, result); err != nil {
+		return nil, fmt.Errorf("failed to store validation result: %w", err)
+	}
+	s.metrics.ObserveValidation(result.Status, result.ProcessingTime)
+
 	logrus.WithFields(logrus.Fields{
-		"transaction_id":   request.TransactionID,
-		"validation_id":    result.ID,
-		"status":           result.Status,
-		"processing_time":  result.ProcessingTime,
-		"rules_processed":  len(result.Rules),
+		"transaction_id":  request.TransactionID,
+		"validation_id":   result.ID,
+		"status":          result.Status,
+		"risk_score":      result.RiskScore,
+		"processing_time": result.ProcessingTime,
+		"rules_processed": len(result.Rules),
 	}).Info("Transaction validation completed")
 
 	return result, nil
 }
 
-// GetValidationResult retrieves a validation result by ID
-func (s *ValidationService) GetValidationResult(validationID string) (*models.ValidationResult, error) {
-	// TODO: Implement actual storage retrieval
-	// For now, return a mock result
-	return &models.ValidationResult{
-		ID:            validationID,
-		TransactionID: "mock-transaction-id",
-		Status:        models.ValidationStatusPassed,
-		ProcessedAt:   time.Now(),
-		ProcessingTime: 50 * time.Millisecond,
-		Rules: []models.RuleResult{
-			{
-				RuleID:      "amount-limit",
-				RuleName:    "Amount Limit Check",
-				Status:      "PASSED",
-				Message:     "Amount within acceptable limits",
-				ProcessedAt: time.Now(),
-			},
-		},
-	}, nil
+// newID returns a unique "<prefix>-<unix nanos>" ID, even for IDs requested in the
+// same nanosecond on different goroutines
+func newID(prefix string) string {
+	for {
+		last := lastIDTimestamp.Load()
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if lastIDTimestamp.CompareAndSwap(last, next) {
+			return fmt.Sprintf("%s-%d", prefix, next)
+		}
+	}
+}
+
+// GetValidationResult retrieves a stored validation result of the tenant in ctx by ID.
+// It returns repository.ErrNotFound when the tenant has no result with the ID.
+func (s *ValidationService) GetValidationResult(ctx context.Context, validationID string) (*models.ValidationResult, error) {
+	result, err := s.results.FindByID(ctx, tenant.FromContext(ctx), validationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve validation result %s: %w", validationID, err)
+	}
+
+	return result, nil
 }
 
-// applyRule applies a single validation rule to a transaction
-func (s *ValidationService) applyRule(rule models.ValidationRule, request *models.ValidationRequest) models.RuleResult {
+// applyRule applies a single validation rule to a transaction. A panic in the rule
+// is recovered and reported as an ERROR result.
+func (s *ValidationService) applyRule(ctx context.Context, rule models.ValidationRule, state *ruleState, request *models.ValidationRequest) (result models.RuleResult) {
 	startTime := time.Now()
 
-	result := models.RuleResult{
+	ctx, span := s.tracer.Start(ctx, "ValidationService.applyRule", trace.WithAttributes(
+		attribute.String("rule.id", rule.ID),
+		attribute.String("rule.type", rule.Type),
+	))
+	defer func() {
+		endRuleSpan(span, result)
+	}()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.WithFields(logrus.Fields{
+				"rule_id":        rule.ID,
+				"transaction_id": request.TransactionID,
+				"panic":          recovered,
+				"stack":          string(debug.Stack()),
+			}).Error("Rule panicked")
+
+			result = models.RuleResult{
+				RuleID:      rule.ID,
+				RuleName:    rule.Name,
+				Status:      "ERROR",
+				Message:     fmt.Sprintf("Rule panicked: %v", recovered),
+				ProcessedAt: startTime,
+			}
+		}
+	}()
+
+	result = models.RuleResult{
 		RuleID:      rule.ID,
 		RuleName:    rule.Name,
 		Status:      "PASSED",
@@ -114,12 +345,18 @@ func (s *ValidationService) applyRule(rule models.ValidationRule, request *model
 
 	// Apply rule logic based on rule type
 	switch rule.Type {
-	case "AMOUNT_LIMIT":
-		result = s.validateAmountLimit(rule, request)
-	case "CURRENCY_CHECK":
+	case RuleTypeAmountLimit:
+		result = s.validateAmountLimit(ctx, rule, state.baseCurrency, request)
+	case RuleTypeCurrencyCheck:
 		result = s.validateCurrency(rule, request)
-	case "COUNTERPARTY_CHECK":
+	case RuleTypeCounterpartyCheck:
 		result = s.validateCounterparty(rule, request)
+	case RuleTypeExpression:
+		result = s.validateExpression(rule, state.program, request)
+	case RuleTypeVelocity:
+		result = s.validateVelocity(ctx, rule, state.velocity, state.baseCurrency, request)
+	case RuleTypeSanctions:
+		result = s.validateSanctions(rule, state.sanctions, request)
 	default:
 		result.Status = "SKIPPED"
 		result.Message = fmt.Sprintf("Unknown rule type: %s", rule.Type)
@@ -137,8 +374,9 @@ func (s *ValidationService) applyRule(rule models.ValidationRule, request *model
 	return result
 }
 
-// validateAmountLimit validates transaction amount against limits
-func (s *ValidationService) validateAmountLimit(rule models.ValidationRule, request *models.ValidationRequest) models.RuleResult {
+// validateAmountLimit validates transaction amount against limits, converting it
+// into the rule's base currency first when one is configured
+func (s *ValidationService) validateAmountLimit(ctx context.Context, rule models.ValidationRule, baseCurrency string, request *models.ValidationRequest) models.RuleResult {
 	result := models.RuleResult{
 		RuleID:   rule.ID,
 		RuleName: rule.Name,
@@ -146,16 +384,31 @@ func (s *ValidationService) validateAmountLimit(rule models.ValidationRule, requ
 	}
 
 	// Get max amount from rule config (default to 1,000,000)
-	maxAmount := 1000000.0
-	if limit, ok := rule.Config["max_amount"].(float64); ok {
+	maxAmount := models.MustParseAmount("1000000")
+	if limit, ok := configAmount(rule.Config, "max_amount"); ok {
 		maxAmount = limit
 	}
 
-	if request.Amount > maxAmount {
+	normalized, err := s.normalizeAmount(ctx, baseCurrency, request)
+	if err != nil {
+		result.Status = conversionFailureStatus(err)
+		result.Message = fmt.Sprintf("Cannot convert %s to %s: %v", request.Currency, baseCurrency, err)
+		return result
+	}
+	result.Metadata = normalized.metadata(request.Amount)
+
+	amount := normalized.amount.Format(normalized.currency)
+	limit := maxAmount.Format(normalized.currency)
+	if normalized.rate != nil {
+		amount = fmt.Sprintf("%s %s (%s %s)", request.Amount.Format(request.Currency), request.Currency, amount, normalized.currency)
+		limit += " " + normalized.currency
+	}
+
+	if normalized.amount.Cmp(maxAmount) > 0 {
 		result.Status = "FAILED"
-		result.Message = fmt.Sprintf("Amount %.2f exceeds maximum limit of %.2f", request.Amount, maxAmount)
+		result.Message = fmt.Sprintf("Amount %s exceeds maximum limit of %s", amount, limit)
 	} else {
-		result.Message = fmt.Sprintf("Amount %.2f is within limit of %.2f", request.Amount, maxAmount)
+		result.Message = fmt.Sprintf("Amount %s is within limit of %s", amount, limit)
 	}
 
 	return result
@@ -171,7 +424,7 @@ func (s *ValidationService) validateCurrency(rule models.ValidationRule, request
 
 	// Get allowed currencies from rule config
 	allowedCurrencies := []string{"USD", "EUR", "GBP", "JPY"}
-	if currencies, ok := rule.Config["allowed_currencies"].([]string); ok {
+	if currencies, ok := configStringSlice(rule.Config, "allowed_currencies"); ok {
 		allowedCurrencies = currencies
 	}
 
@@ -222,11 +475,11 @@ func getDefaultValidationRules() []models.ValidationRule {
 			ID:          "amount-limit",
 			Name:        "Amount Limit Check",
 			Description: "Validates transaction amount against maximum limits",
-			Type:        "AMOUNT_LIMIT",
+			Type:        RuleTypeAmountLimit,
 			Enabled:     true,
 			Priority:    1,
 			Config: map[string]interface{}{
-				"max_amount": 1000000.0,
+				"max_amount": "1000000",
 			},
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -235,7 +488,7 @@ func getDefaultValidationRules() []models.ValidationRule {
 			ID:          "currency-check",
 			Name:        "Currency Validation",
 			Description: "Validates transaction currency against allowed currencies",
-			Type:        "CURRENCY_CHECK",
+			Type:        RuleTypeCurrencyCheck,
 			Enabled:     true,
 			Priority:    2,
 			Config: map[string]interface{}{
@@ -248,7 +501,7 @@ func getDefaultValidationRules() []models.ValidationRule {
 			ID:          "counterparty-check",
 			Name:        "Counterparty Validation",
 			Description: "Validates counterparty information completeness",
-			Type:        "COUNTERPARTY_CHECK",
+			Type:        RuleTypeCounterpartyCheck,
 			Enabled:     true,
 			Priority:    3,
 			Config:      map[string]interface{}{},
@@ -256,4 +509,4 @@ func getDefaultValidationRules() []models.ValidationRule {
 			UpdatedAt:   time.Now(),
 		},
 	}
-}
\ No newline at end of file
+}
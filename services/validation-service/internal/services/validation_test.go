@@ -1,20 +1,25 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/gtrs/validation-service/internal/auth"
 	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/gtrs/validation-service/internal/velocity"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidationService_ValidateTransaction_Success(t *testing.T) {
-	service := NewValidationService()
+	service := NewValidationService(repository.NewMemoryResultRepository())
 
 	request := &models.ValidationRequest{
 		TransactionID: "test-txn-123",
 		Type:          "PAYMENT",
-		Amount:        1000.00,
+		Amount:        models.MustParseAmount("1000.00"),
 		Currency:      "USD",
 		Counterparty: models.Counterparty{
 			ID:   "cp-123",
@@ -24,7 +29,7 @@ func TestValidationService_ValidateTransaction_Success(t *testing.T) {
 		Timestamp: time.Now(),
 	}
 
-	result, err := service.ValidateTransaction(request)
+	result, err := service.ValidateTransaction(context.Background(), request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -36,12 +41,12 @@ func TestValidationService_ValidateTransaction_Success(t *testing.T) {
 }
 
 func TestValidationService_ValidateTransaction_AmountExceedsLimit(t *testing.T) {
-	service := NewValidationService()
+	service := NewValidationService(repository.NewMemoryResultRepository())
 
 	request := &models.ValidationRequest{
 		TransactionID: "test-txn-456",
 		Type:          "PAYMENT",
-		Amount:        2000000.00, // Exceeds default limit of 1,000,000
+		Amount:        models.MustParseAmount("2000000.00"), // Exceeds default limit of 1,000,000
 		Currency:      "USD",
 		Counterparty: models.Counterparty{
 			ID:   "cp-456",
@@ -51,7 +56,7 @@ func TestValidationService_ValidateTransaction_AmountExceedsLimit(t *testing.T)
 		Timestamp: time.Now(),
 	}
 
-	result, err := service.ValidateTransaction(request)
+	result, err := service.ValidateTransaction(context.Background(), request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -71,12 +76,12 @@ func TestValidationService_ValidateTransaction_AmountExceedsLimit(t *testing.T)
 }
 
 func TestValidationService_ValidateTransaction_InvalidCurrency(t *testing.T) {
-	service := NewValidationService()
+	service := NewValidationService(repository.NewMemoryResultRepository())
 
 	request := &models.ValidationRequest{
 		TransactionID: "test-txn-789",
 		Type:          "PAYMENT",
-		Amount:        1000.00,
+		Amount:        models.MustParseAmount("1000.00"),
 		Currency:      "XYZ", // Invalid currency
 		Counterparty: models.Counterparty{
 			ID:   "cp-789",
@@ -86,7 +91,7 @@ func TestValidationService_ValidateTransaction_InvalidCurrency(t *testing.T) {
 		Timestamp: time.Now(),
 	}
 
-	result, err := service.ValidateTransaction(request)
+	result, err := service.ValidateTransaction(context.Background(), request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -104,12 +109,12 @@ func TestValidationService_ValidateTransaction_InvalidCurrency(t *testing.T) {
 }
 
 func TestValidationService_ValidateTransaction_MissingCounterparty(t *testing.T) {
-	service := NewValidationService()
+	service := NewValidationService(repository.NewMemoryResultRepository())
 
 	request := &models.ValidationRequest{
 		TransactionID: "test-txn-000",
 		Type:          "PAYMENT",
-		Amount:        1000.00,
+		Amount:        models.MustParseAmount("1000.00"),
 		Currency:      "USD",
 		Counterparty: models.Counterparty{
 			ID:   "", // Missing counterparty ID
@@ -119,7 +124,7 @@ func TestValidationService_ValidateTransaction_MissingCounterparty(t *testing.T)
 		Timestamp: time.Now(),
 	}
 
-	result, err := service.ValidateTransaction(request)
+	result, err := service.ValidateTransaction(context.Background(), request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -137,12 +142,130 @@ func TestValidationService_ValidateTransaction_MissingCounterparty(t *testing.T)
 }
 
 func TestValidationService_GetValidationResult(t *testing.T) {
-	service := NewValidationService()
+	service := NewValidationService(repository.NewMemoryResultRepository())
 
-	result, err := service.GetValidationResult("test-validation-id")
+	request := &models.ValidationRequest{
+		TransactionID: "test-txn-321",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("2000000.00"),
+		Currency:      "USD",
+		Counterparty: models.Counterparty{
+			ID:   "cp-321",
+			Name: "Test Corp",
+			Type: "BUSINESS",
+		},
+		Timestamp: time.Now(),
+	}
+
+	validated, err := service.ValidateTransaction(context.Background(), request)
+	assert.NoError(t, err)
+
+	result, err := service.GetValidationResult(context.Background(), validated.ID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Equal(t, "test-validation-id", result.ID)
+	assert.Equal(t, validated.ID, result.ID)
+	assert.Equal(t, "test-txn-321", result.TransactionID)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+	assert.Equal(t, len(validated.Rules), len(result.Rules))
+}
+
+func TestValidationService_GetValidationResult_NotFound(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	result, err := service.GetValidationResult(context.Background(), "does-not-exist")
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, result)
+}
+func TestValidationService_ValidateTransaction_ExactAmountLimit(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	request := &models.ValidationRequest{
+		TransactionID: "test-txn-exact",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("1000000.00"),
+		Currency:      "USD",
+		Counterparty: models.Counterparty{
+			ID:   "cp-exact",
+			Name: "Test Corp",
+			Type: "BUSINESS",
+		},
+	}
+
+	result, err := service.ValidateTransaction(context.Background(), request)
+	assert.NoError(t, err)
 	assert.Equal(t, models.ValidationStatusPassed, result.Status)
-}
\ No newline at end of file
+
+	request.Amount = models.MustParseAmount("1000000.01")
+	result, err = service.ValidateTransaction(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+}
+
+func TestValidationService_ValidateTransaction_TooManyDecimals(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	result, err := service.ValidateTransaction(context.Background(), &models.ValidationRequest{
+		TransactionID: "test-txn-jpy",
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount("100.5"),
+		Currency:      "JPY",
+		Counterparty: models.Counterparty{
+			ID:   "cp-jpy",
+			Name: "Test Corp",
+			Type: "BUSINESS",
+		},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.Nil(t, result)
+}
+
+// panickingVelocityStore makes any VELOCITY rule crash
+type panickingVelocityStore struct{}
+
+func (panickingVelocityStore) Record(ctx context.Context, key string, event velocity.Event, windows []time.Duration) ([]velocity.Totals, error) {
+	panic("nil map in store")
+}
+
+func TestValidationService_RulePanicIsolated(t *testing.T) {
+	for name, timeouts := range map[string]Timeouts{
+		"inline":       {},
+		"with timeout": {Rule: time.Second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			service := NewValidationService(repository.NewMemoryResultRepository(),
+				WithVelocityStore(panickingVelocityStore{}), WithTimeouts(timeouts))
+			_, err := service.CreateRule(context.Background(), newVelocityRule(map[string]interface{}{
+				"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 3}},
+			}))
+			require.NoError(t, err)
+
+			result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-1", "100"))
+			require.NoError(t, err)
+
+			ruleResult := findRuleResult(result, "counterparty-velocity")
+			require.NotNil(t, ruleResult)
+			assert.Equal(t, "ERROR", ruleResult.Status)
+			assert.Contains(t, ruleResult.Message, "nil map in store")
+
+			// The remaining rules still ran
+			assert.Len(t, result.Rules, 4)
+			assert.Equal(t, "PASSED", findRuleResult(result, "amount-limit").Status)
+		})
+	}
+}
+
+func TestValidationService_ValidateTransaction_RecordsCaller(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+
+	result, err := service.ValidateTransaction(context.Background(), newRiskRequest("100"))
+	require.NoError(t, err)
+	assert.Nil(t, result.RequestedBy)
+
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "transaction-service", Method: auth.MethodAPIKey})
+	result, err = service.ValidateTransaction(ctx, newRiskRequest("100"))
+	require.NoError(t, err)
+	assert.Equal(t, &models.Caller{Subject: "transaction-service", Method: auth.MethodAPIKey}, result.RequestedBy)
+}
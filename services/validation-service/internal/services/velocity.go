@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/tenant"
+	"github.com/gtrs/validation-service/internal/velocity"
+)
+
+// defaultVelocityKey groups transactions by counterparty unless the rule says otherwise
+const defaultVelocityKey = "counterparty.id"
+
+// velocityConfig is the parsed config of a VELOCITY rule
+type velocityConfig struct {
+	key    string
+	limits []velocityLimit
+}
+
+// velocityLimit caps the count and/or total amount of transactions within one window
+type velocityLimit struct {
+	window    time.Duration
+	label     string
+	maxCount  int            // 0 means no count limit
+	maxAmount *models.Amount // nil means no amount limit
+}
+
+// parseVelocityConfig reads and validates a VELOCITY rule config:
+//
+//	key: counterparty.id            # or counterparty.name, currency, type, metadata.<field>
+//	limits:
+//	  - window: 1h
+//	    max_count: 10
+//	  - window: 24h
+//	    max_amount: 50000
+//	base_currency: USD              # totals are kept in this currency; required with max_amount
+//
+// Amount limits need a base currency, as totals of amounts in different currencies
+// cannot be compared with one limit.
+func parseVelocityConfig(rule models.ValidationRule) (*velocityConfig, error) {
+	cfg := &velocityConfig{key: defaultVelocityKey}
+
+	if key, present := rule.Config["key"]; present {
+		keyStr, ok := key.(string)
+		if !ok || !isVelocityKey(keyStr) {
+			return nil, fmt.Errorf("%w: %s config key must be counterparty.id, counterparty.name, currency, type or metadata.<field>", ErrInvalidRule, rule.Type)
+		}
+		cfg.key = keyStr
+	}
+
+	rawLimits, ok := rule.Config["limits"].([]interface{})
+	if !ok || len(rawLimits) == 0 {
+		return nil, fmt.Errorf("%w: %s config limits must be a non-empty list", ErrInvalidRule, rule.Type)
+	}
+
+	amountLimited := false
+	for i, raw := range rawLimits {
+		limitConfig, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s config limits[%d] must be an object", ErrInvalidRule, rule.Type, i)
+		}
+
+		windowStr, _ := limitConfig["window"].(string)
+		window, err := time.ParseDuration(windowStr)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("%w: %s config limits[%d].window must be a positive duration such as \"1h\"", ErrInvalidRule, rule.Type, i)
+		}
+
+		limit := velocityLimit{window: window, label: windowStr}
+
+		if _, present := limitConfig["max_count"]; present {
+			maxCount, ok := configFloat(limitConfig, "max_count")
+			if !ok || maxCount < 1 || maxCount != float64(int(maxCount)) {
+				return nil, fmt.Errorf("%w: %s config limits[%d].max_count must be a positive integer", ErrInvalidRule, rule.Type, i)
+			}
+			limit.maxCount = int(maxCount)
+		}
+
+		if _, present := limitConfig["max_amount"]; present {
+			maxAmount, ok := configAmount(limitConfig, "max_amount")
+			if !ok || !maxAmount.IsPositive() {
+				return nil, fmt.Errorf("%w: %s config limits[%d].max_amount must be a positive number", ErrInvalidRule, rule.Type, i)
+			}
+			limit.maxAmount = &maxAmount
+			amountLimited = true
+		}
+
+		if limit.maxCount == 0 && limit.maxAmount == nil {
+			return nil, fmt.Errorf("%w: %s config limits[%d] needs max_count or max_amount", ErrInvalidRule, rule.Type, i)
+		}
+
+		cfg.limits = append(cfg.limits, limit)
+	}
+
+	if _, present := rule.Config["base_currency"]; amountLimited && !present {
+		return nil, fmt.Errorf("%w: %s config base_currency is required with max_amount", ErrInvalidRule, rule.Type)
+	}
+
+	return cfg, nil
+}
+
+// validateVelocity records the transaction and checks it against each window limit.
+// With a base currency, amounts are converted before being added to the totals.
+func (s *ValidationService) validateVelocity(ctx context.Context, rule models.ValidationRule, cfg *velocityConfig, baseCurrency string, request *models.ValidationRequest) models.RuleResult {
+	result := models.RuleResult{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Status:   "PASSED",
+	}
+
+	keyValue := velocityKeyValue(cfg.key, request)
+	if keyValue == "" {
+		result.Status = "SKIPPED"
+		result.Message = fmt.Sprintf("No value for velocity key %s", cfg.key)
+		return result
+	}
+
+	normalized, err := s.normalizeAmount(ctx, baseCurrency, request)
+	if err != nil {
+		result.Status = conversionFailureStatus(err)
+		result.Message = fmt.Sprintf("Cannot convert %s to %s: %v", request.Currency, baseCurrency, err)
+		return result
+	}
+	result.Metadata = normalized.metadata(request.Amount)
+
+	windows := make([]time.Duration, len(cfg.limits))
+	for i, limit := range cfg.limits {
+		windows[i] = limit.window
+	}
+
+	event := velocity.Event{
+		ID:     request.TransactionID,
+		Amount: normalized.amount.Decimal(),
+		At:     time.Now(),
+	}
+
+	// Tenants' windows are kept apart even when their rule IDs and keys coincide
+	storeKey := rule.ID + ":" + keyValue
+	if tenantID := tenant.FromContext(ctx); tenantID != tenant.Default {
+		storeKey = tenantID + ":" + storeKey
+	}
+	totals, err := s.velocityStore.Record(ctx, storeKey, event, windows)
+	if err != nil {
+		result.Status = "ERROR"
+		result.Message = fmt.Sprintf("Velocity totals unavailable: %v", err)
+		return result
+	}
+
+	for i, limit := range cfg.limits {
+		if limit.maxCount > 0 && totals[i].Count > limit.maxCount {
+			result.Status = "FAILED"
+			result.Message = fmt.Sprintf("%d transactions for %s %s in %s exceeds limit of %d",
+				totals[i].Count, cfg.key, keyValue, limit.label, limit.maxCount)
+			return result
+		}
+		total := models.NewAmount(totals[i].Amount)
+		if limit.maxAmount != nil && total.Cmp(*limit.maxAmount) > 0 {
+			result.Status = "FAILED"
+			result.Message = fmt.Sprintf("Total amount %s for %s %s in %s exceeds limit of %s",
+				total.Format(normalized.currency), cfg.key, keyValue, limit.label, limit.maxAmount.Format(normalized.currency))
+			return result
+		}
+	}
+
+	result.Message = fmt.Sprintf("Velocity for %s %s is within limits", cfg.key, keyValue)
+	return result
+}
+
+func isVelocityKey(key string) bool {
+	switch key {
+	case "counterparty.id", "counterparty.name", "currency", "type":
+		return true
+	default:
+		return strings.HasPrefix(key, "metadata.") && len(key) > len("metadata.")
+	}
+}
+
+// velocityKeyValue extracts the grouping value for a velocity key from the request
+func velocityKeyValue(key string, request *models.ValidationRequest) string {
+	switch key {
+	case "counterparty.id":
+		return request.Counterparty.ID
+	case "counterparty.name":
+		return request.Counterparty.Name
+	case "currency":
+		return request.Currency
+	case "type":
+		return request.Type
+	}
+
+	value, ok := request.Metadata[strings.TrimPrefix(key, "metadata.")]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
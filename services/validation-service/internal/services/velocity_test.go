@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gtrs/validation-service/internal/models"
+	"github.com/gtrs/validation-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVelocityRule(config map[string]interface{}) models.ValidationRule {
+	return models.ValidationRule{
+		ID:      "counterparty-velocity",
+		Name:    "Counterparty Velocity",
+		Type:    RuleTypeVelocity,
+		Enabled: true,
+		Config:  config,
+	}
+}
+
+func newVelocityRequest(id string, amount string) *models.ValidationRequest {
+	return &models.ValidationRequest{
+		TransactionID: id,
+		Type:          "PAYMENT",
+		Amount:        models.MustParseAmount(amount),
+		Currency:      "USD",
+		Counterparty:  models.Counterparty{ID: "cp-split", Name: "Test Corp", Type: "BUSINESS"},
+		Metadata:      map[string]interface{}{"device_id": "dev-1"},
+	}
+}
+
+func TestValidationService_VelocityRule_CountLimit(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	_, err := service.CreateRule(context.Background(), newVelocityRule(map[string]interface{}{
+		"limits": []interface{}{
+			map[string]interface{}{"window": "1h", "max_count": 3},
+		},
+	}))
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		result, err := service.ValidateTransaction(context.Background(), newVelocityRequest(fmt.Sprintf("txn-%d", i), "100"))
+		require.NoError(t, err)
+		assert.Equal(t, models.ValidationStatusPassed, result.Status)
+	}
+
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-4", "100"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "FAILED", ruleResult.Status)
+	assert.Contains(t, ruleResult.Message, "4 transactions")
+}
+
+func TestValidationService_VelocityRule_AmountLimitByMetadata(t *testing.T) {
+	service := NewValidationService(repository.NewMemoryResultRepository())
+	_, err := service.CreateRule(context.Background(), newVelocityRule(map[string]interface{}{
+		"key":           "metadata.device_id",
+		"base_currency": "USD",
+		"limits": []interface{}{
+			map[string]interface{}{"window": "24h", "max_amount": 50000},
+		},
+	}))
+	require.NoError(t, err)
+
+	result, err := service.ValidateTransaction(context.Background(), newVelocityRequest("txn-1", "30000"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusPassed, result.Status)
+
+	result, err = service.ValidateTransaction(context.Background(), newVelocityRequest("txn-2", "30000"))
+	require.NoError(t, err)
+	assert.Equal(t, models.ValidationStatusFailed, result.Status)
+
+	// Transactions without the metadata field are not tracked
+	request := newVelocityRequest("txn-3", "30000")
+	request.Metadata = nil
+	result, err = service.ValidateTransaction(context.Background(), request)
+	require.NoError(t, err)
+	ruleResult := findRuleResult(result, "counterparty-velocity")
+	require.NotNil(t, ruleResult)
+	assert.Equal(t, "SKIPPED", ruleResult.Status)
+}
+
+func TestValidateRule_InvalidVelocityConfig(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"no limits":     {},
+		"bad window":    {"limits": []interface{}{map[string]interface{}{"window": "hourly", "max_count": 1}}},
+		"no maximum":    {"limits": []interface{}{map[string]interface{}{"window": "1h"}}},
+		"partial count": {"limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 1.5}}},
+		"bad key":       {"key": "amount", "limits": []interface{}{map[string]interface{}{"window": "1h", "max_count": 1}}},
+		"amount without base currency": {"limits": []interface{}{
+			map[string]interface{}{"window": "1h", "max_count": 5},
+			map[string]interface{}{"window": "24h", "max_amount": 50000},
+		}},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateRule(newVelocityRule(config), ""), ErrInvalidRule)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrInvalidTenant is returned for a tenant ID that is not a valid identifier
+	ErrInvalidTenant = errors.New("invalid tenant ID")
+
+	// ErrTenantMismatch is returned when a caller bound to one tenant asks for another
+	ErrTenantMismatch = errors.New("caller is not allowed to act for this tenant")
+)
+
+// Header is the HTTP header selecting the tenant of a request
+const Header = "X-Tenant-ID"
+
+// MetadataKey is the gRPC metadata key selecting the tenant of a call
+const MetadataKey = "x-tenant-id"
+
+// Default is the tenant of requests that do not name one. It uses the global rule set.
+const Default = ""
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// Validate checks that id is empty or a tenant identifier of up to 64 letters, digits,
+// dots, dashes and underscores
+func Validate(id string) error {
+	if id != Default && !idPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidTenant, id)
+	}
+	return nil
+}
+
+// Resolve selects the tenant of a request from the tenant the caller is bound to, if
+// any, and the tenant it requested. A bound caller may only act for its own tenant; an
+// unbound one may only name another tenant than the default when anyTenant is set.
+func Resolve(bound, requested string, anyTenant bool) (string, error) {
+	if bound != Default {
+		if requested != Default && requested != bound {
+			return "", fmt.Errorf("%w: %s", ErrTenantMismatch, requested)
+		}
+		return bound, nil
+	}
+	if err := Validate(requested); err != nil {
+		return "", err
+	}
+	if requested != Default && !anyTenant {
+		return "", fmt.Errorf("%w: %s", ErrTenantMismatch, requested)
+	}
+	return requested, nil
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID stored in ctx, or Default
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
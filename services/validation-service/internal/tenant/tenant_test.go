@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		bound     string
+		requested string
+		anyTenant bool
+		expected  string
+		err       error
+	}{
+		{"default", "", "", false, Default, nil},
+		{"requested", "", "retail", true, "retail", nil},
+		{"requested without permission", "", "retail", false, "", ErrTenantMismatch},
+		{"bound", "retail", "", false, "retail", nil},
+		{"bound and requested", "retail", "retail", false, "retail", nil},
+		{"other tenant", "retail", "corporate", true, "", ErrTenantMismatch},
+		{"invalid", "", "../retail", true, "", ErrInvalidTenant},
+		{"too long", "", "t" + string(make([]byte, 64)), true, "", ErrInvalidTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := Resolve(tt.bound, tt.requested, tt.anyTenant)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "retail", FromContext(NewContext(context.Background(), "retail")))
+}
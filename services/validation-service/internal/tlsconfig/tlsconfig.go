@@ -0,0 +1,225 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadDebounce groups the several events written while a certificate is rotated
+const reloadDebounce = 500 * time.Millisecond
+
+// Client certificate modes
+const (
+	ClientAuthNone     = "none"     // client certificates are not requested
+	ClientAuthOptional = "optional" // verified when presented
+	ClientAuthRequire  = "require"  // every connection must present a valid certificate
+)
+
+// Config lists the files of the server certificate and the CA bundle that client
+// certificates are verified against
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   string // none, optional or require
+}
+
+// Enabled reports whether TLS is configured
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// clientAuthType maps the configured mode to the crypto/tls setting
+func (c Config) clientAuthType() (tls.ClientAuthType, error) {
+	switch c.ClientAuth {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unknown client auth mode %q: must be none, optional or require", c.ClientAuth)
+	}
+}
+
+// material is one loaded generation of certificate and CA files
+type material struct {
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+}
+
+// Manager serves the current certificate and client CA bundle to new connections and
+// reloads them when their files change. Connections already established keep the
+// certificates they were opened with.
+type Manager struct {
+	cfg        Config
+	clientAuth tls.ClientAuthType
+
+	mu      sync.RWMutex
+	current material
+}
+
+// New loads the configured files. Client certificates can only be verified when a
+// client CA file is configured.
+func New(cfg Config) (*Manager, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("both a certificate and a key file are required")
+	}
+	clientAuth, err := cfg.clientAuthType()
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth != tls.NoClientCert && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("client auth mode %s needs a client CA file", cfg.ClientAuth)
+	}
+
+	m := &Manager{cfg: cfg, clientAuth: clientAuth}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// VerifiesClients reports whether client certificates are verified
+func (m *Manager) VerifiesClients() bool {
+	return m.clientAuth != tls.NoClientCert
+}
+
+// Reload reads the certificate, key and client CA files again. On error the previous
+// certificates stay in use.
+func (m *Manager) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(m.cfg.CertFile, m.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate %s: %w", m.cfg.CertFile, err)
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parse certificate %s: %w", m.cfg.CertFile, err)
+	}
+	certificate.Leaf = leaf
+
+	var clientCAs *x509.CertPool
+	if m.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(m.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client CA file %s contains no certificates", m.cfg.ClientCAFile)
+		}
+	}
+
+	m.mu.Lock()
+	m.current = material{certificate: &certificate, clientCAs: clientCAs}
+	m.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"cert_file": m.cfg.CertFile,
+		"subject":   leaf.Subject.String(),
+		"not_after": leaf.NotAfter,
+	}).Info("TLS certificates loaded")
+	return nil
+}
+
+// TLSConfig returns a server configuration that picks up reloaded certificates on
+// each handshake
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return m.load().certificate, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			current := m.load()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*current.certificate},
+				ClientAuth:   m.clientAuth,
+				ClientCAs:    current.clientCAs,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// load returns the current certificates
+func (m *Manager) load() material {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Watch reloads the certificates whenever one of their files changes, until ctx is
+// done. The directories holding the files are watched so that files replaced by a
+// rename or a symlink swap, as Kubernetes does with mounted secrets, are noticed.
+func (m *Manager) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create certificate watcher: %w", err)
+	}
+
+	for _, dir := range m.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("watch certificate directory %s: %w", dir, err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				debounce = time.After(reloadDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.WithError(err).Warn("Certificate watcher error")
+			case <-debounce:
+				debounce = nil
+				if err := m.Reload(); err != nil {
+					logrus.WithError(err).Error("Rejected certificate change, keeping previous certificates")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// dirs returns the distinct directories of the configured files
+func (m *Manager) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range []string{m.cfg.CertFile, m.cfg.KeyFile, m.cfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,247 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues certificates for the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns PEM encoded certificate and key for a server (localhost) or client
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeServerFiles writes a server certificate and key issued by ca into dir
+func writeServerFiles(t *testing.T, ca *testCA, dir, commonName string) Config {
+	t.Helper()
+	cfg := Config{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	certPEM, keyPEM := ca.issue(t, commonName, x509.ExtKeyUsageServerAuth)
+	require.NoError(t, os.WriteFile(cfg.CertFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.KeyFile, keyPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.ClientCAFile, ca.pem, 0o600))
+	return cfg
+}
+
+// serve accepts TLS connections and completes their handshakes until the test ends
+func serve(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// dial connects to addr trusting ca, presenting the client certificate if given, and
+// returns the common name of the server certificate
+func dial(addr string, ca *testCA, client *tls.Certificate) (string, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if client != nil {
+		config.Certificates = []tls.Certificate{*client}
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, config)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	// With TLS 1.3 a rejected client certificate surfaces on the first read
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestNew_Invalid(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := writeServerFiles(t, ca, t.TempDir(), "server")
+
+	tests := map[string]Config{
+		"missing key":          {CertFile: cfg.CertFile},
+		"unknown mode":         {CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientAuth: "always"},
+		"mode without CA file": {CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientAuth: ClientAuthRequire},
+		"key mismatch":         {CertFile: cfg.CertFile, KeyFile: cfg.ClientCAFile},
+		"empty CA file":        {CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientCAFile: cfg.KeyFile, ClientAuth: ClientAuthOptional},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(tt)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestManager_ClientAuth(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := writeServerFiles(t, ca, t.TempDir(), "server")
+	clientPEM, clientKey := ca.issue(t, "transaction-service", x509.ExtKeyUsageClientAuth)
+	client, err := tls.X509KeyPair(clientPEM, clientKey)
+	require.NoError(t, err)
+
+	other := newTestCA(t)
+	otherPEM, otherKey := other.issue(t, "intruder", x509.ExtKeyUsageClientAuth)
+	untrusted, err := tls.X509KeyPair(otherPEM, otherKey)
+	require.NoError(t, err)
+
+	t.Run("optional", func(t *testing.T) {
+		cfg.ClientAuth = ClientAuthOptional
+		manager, err := New(cfg)
+		require.NoError(t, err)
+		assert.True(t, manager.VerifiesClients())
+		addr := serve(t, manager.TLSConfig())
+
+		_, err = dial(addr, ca, nil)
+		assert.NoError(t, err)
+		_, err = dial(addr, ca, &client)
+		assert.NoError(t, err)
+		_, err = dial(addr, ca, &untrusted)
+		assert.Error(t, err)
+	})
+
+	t.Run("require", func(t *testing.T) {
+		cfg.ClientAuth = ClientAuthRequire
+		manager, err := New(cfg)
+		require.NoError(t, err)
+		addr := serve(t, manager.TLSConfig())
+
+		_, err = dial(addr, ca, nil)
+		assert.Error(t, err)
+		_, err = dial(addr, ca, &client)
+		assert.NoError(t, err)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		cfg.ClientAuth = ClientAuthNone
+		manager, err := New(cfg)
+		require.NoError(t, err)
+		assert.False(t, manager.VerifiesClients())
+		addr := serve(t, manager.TLSConfig())
+
+		_, err = dial(addr, ca, &untrusted)
+		assert.NoError(t, err, "client certificates are not requested")
+	})
+}
+
+func TestManager_Reload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := writeServerFiles(t, ca, dir, "server-1")
+	manager, err := New(cfg)
+	require.NoError(t, err)
+	addr := serve(t, manager.TLSConfig())
+
+	name, err := dial(addr, ca, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "server-1", name)
+
+	writeServerFiles(t, ca, dir, "server-2")
+	require.NoError(t, manager.Reload())
+	name, err = dial(addr, ca, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "server-2", name)
+
+	// A broken rotation keeps the previous certificate
+	require.NoError(t, os.WriteFile(cfg.KeyFile, []byte("not a key"), 0o600))
+	assert.Error(t, manager.Reload())
+	name, err = dial(addr, ca, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "server-2", name)
+}
+
+func TestManager_Watch(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	manager, err := New(writeServerFiles(t, ca, dir, "server-1"))
+	require.NoError(t, err)
+	addr := serve(t, manager.TLSConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, manager.Watch(ctx))
+
+	writeServerFiles(t, ca, dir, "server-2")
+	require.Eventually(t, func() bool {
+		name, err := dial(addr, ca, nil)
+		return err == nil && name == "server-2"
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_None(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone, SampleRatio: 1})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// Trace context is still propagated without an exporter
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout, SampleRatio: 1, ServiceName: "test"})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestSetup_Invalid(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin", SampleRatio: 1})
+	assert.ErrorContains(t, err, "unknown tracing exporter")
+
+	_, err = Setup(context.Background(), Config{Exporter: ExporterStdout, SampleRatio: 2})
+	assert.ErrorContains(t, err, "sample ratio")
+}
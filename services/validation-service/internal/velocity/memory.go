@@ -0,0 +1,119 @@
+package velocity
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle keys are purged from the memory store
+const sweepInterval = time.Minute
+
+// MemoryStore keeps velocity events in process memory. Totals are per instance,
+// so deployments with several replicas need a shared store such as Redis.
+type MemoryStore struct {
+	mu        sync.Mutex
+	keys      map[string]*keyEvents
+	lastSweep time.Time
+}
+
+type keyEvents struct {
+	events    []Event // ordered by At
+	retention time.Duration
+}
+
+// NewMemoryStore creates an empty in-memory velocity store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys:      make(map[string]*keyEvents),
+		lastSweep: time.Now(),
+	}
+}
+
+// Record adds the event and returns totals for each window
+func (s *MemoryStore) Record(ctx context.Context, key string, event Event, windows []time.Duration) ([]Totals, error) {
+	var retention time.Duration
+	for _, window := range windows {
+		if window > retention {
+			retention = window
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.keys[key]
+	if !ok {
+		entry = &keyEvents{}
+		s.keys[key] = entry
+	}
+	if retention > entry.retention {
+		entry.retention = retention
+	}
+
+	if event.ID == "" || !entry.contains(event.ID) {
+		entry.insert(event)
+	}
+	entry.prune(event.At)
+
+	totals := make([]Totals, len(windows))
+	for i, window := range windows {
+		since := event.At.Add(-window)
+		for _, e := range entry.events {
+			if e.At.After(since) && !e.At.After(event.At) {
+				totals[i].Count++
+				totals[i].Amount = totals[i].Amount.Add(e.Amount)
+			}
+		}
+	}
+
+	if event.At.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(event.At)
+	}
+
+	return totals, nil
+}
+
+// contains reports whether an event with the ID is recorded
+func (k *keyEvents) contains(id string) bool {
+	for _, e := range k.events {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// insert adds an event, keeping events ordered by time
+func (k *keyEvents) insert(event Event) {
+	i := len(k.events)
+	for i > 0 && k.events[i-1].At.After(event.At) {
+		i--
+	}
+	k.events = append(k.events, Event{})
+	copy(k.events[i+1:], k.events[i:])
+	k.events[i] = event
+}
+
+// prune drops events that are older than the retention period
+func (k *keyEvents) prune(now time.Time) {
+	cutoff := now.Add(-k.retention)
+	i := 0
+	for i < len(k.events) && !k.events[i].At.After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		k.events = append(k.events[:0], k.events[i:]...)
+	}
+}
+
+// sweep removes expired events from every key and drops keys left empty
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.keys {
+		entry.prune(now)
+		if len(entry.events) == 0 {
+			delete(s.keys, key)
+		}
+	}
+	s.lastSweep = now
+}
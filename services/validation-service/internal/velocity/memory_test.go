@@ -0,0 +1,81 @@
+package velocity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Record_SlidingWindows(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	windows := []time.Duration{time.Hour, 24 * time.Hour}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := store.Record(ctx, "cp-1", Event{ID: "t1", Amount: decimal.NewFromInt(100), At: start}, windows)
+	require.NoError(t, err)
+	_, err = store.Record(ctx, "cp-1", Event{ID: "t2", Amount: decimal.NewFromInt(200), At: start.Add(45 * time.Minute)}, windows)
+	require.NoError(t, err)
+
+	totals, err := store.Record(ctx, "cp-1", Event{ID: "t3", Amount: decimal.NewFromInt(300), At: start.Add(90 * time.Minute)}, windows)
+	require.NoError(t, err)
+
+	// The first event has slid out of the 1h window but is still within 24h
+	assert.Equal(t, Totals{Count: 2, Amount: decimal.NewFromInt(500)}, totals[0])
+	assert.Equal(t, Totals{Count: 3, Amount: decimal.NewFromInt(600)}, totals[1])
+}
+
+func TestMemoryStore_Record_KeysAreIndependent(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	windows := []time.Duration{time.Hour}
+	now := time.Now()
+
+	_, err := store.Record(ctx, "cp-1", Event{ID: "t1", Amount: decimal.NewFromInt(100), At: now}, windows)
+	require.NoError(t, err)
+
+	totals, err := store.Record(ctx, "cp-2", Event{ID: "t2", Amount: decimal.NewFromInt(50), At: now}, windows)
+	require.NoError(t, err)
+
+	assert.Equal(t, Totals{Count: 1, Amount: decimal.NewFromInt(50)}, totals[0])
+}
+
+func TestMemoryStore_Record_DuplicateEvent(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	windows := []time.Duration{time.Hour}
+	now := time.Now()
+
+	_, err := store.Record(ctx, "cp-1", Event{ID: "t1", Amount: decimal.NewFromInt(100), At: now}, windows)
+	require.NoError(t, err)
+
+	// A retried transaction is counted once
+	totals, err := store.Record(ctx, "cp-1", Event{ID: "t1", Amount: decimal.NewFromInt(100), At: now.Add(time.Second)}, windows)
+	require.NoError(t, err)
+	assert.Equal(t, Totals{Count: 1, Amount: decimal.NewFromInt(100)}, totals[0])
+
+	totals, err = store.Record(ctx, "cp-1", Event{ID: "t2", Amount: decimal.NewFromInt(50), At: now.Add(2 * time.Second)}, windows)
+	require.NoError(t, err)
+	assert.Equal(t, Totals{Count: 2, Amount: decimal.NewFromInt(150)}, totals[0])
+}
+
+func TestMemoryStore_Sweep_DropsExpiredKeys(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	windows := []time.Duration{time.Minute}
+	start := time.Now()
+
+	_, err := store.Record(ctx, "old", Event{ID: "t1", Amount: decimal.NewFromInt(1), At: start}, windows)
+	require.NoError(t, err)
+	_, err = store.Record(ctx, "new", Event{ID: "t2", Amount: decimal.NewFromInt(1), At: start.Add(2 * sweepInterval)}, windows)
+	require.NoError(t, err)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.NotContains(t, store.keys, "old")
+	assert.Contains(t, store.keys, "new")
+}
@@ -0,0 +1,33 @@
+package velocity
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Event is a single transaction counted towards velocity limits
+type Event struct {
+	ID     string // transaction ID; an event is counted once per key however often it is recorded
+	Amount decimal.Decimal
+	At     time.Time
+}
+
+// Totals aggregates the events that fall within one window
+type Totals struct {
+	Count  int
+	Amount decimal.Decimal
+}
+
+// Store records transaction events per key and reports totals over sliding windows.
+// Implementations must make Record atomic per key so concurrent transactions for the
+// same key all see each other (a Redis implementation can use a sorted set scored by
+// event time inside MULTI/EXEC).
+type Store interface {
+	// Record adds the event under key and returns, for each window, the totals of all
+	// events in (event.At - window, event.At], including the new event. An event whose
+	// ID is already recorded under key, such as a retried transaction, is not added
+	// again. Events older than the largest window may be discarded.
+	Record(ctx context.Context, key string, event Event, windows []time.Duration) ([]Totals, error)
+}